@@ -0,0 +1,156 @@
+package decimal
+
+import "strings"
+
+// Condition is a bitmask of the exceptional conditions an operation can
+// raise. Conditions are sticky: an operation raises them in its result's
+// Context and they stay there until they're cleared with SetConditions.
+//
+// Each condition is either recorded or trapped. A recorded condition only
+// sets its flag. A trapped condition, set with SetTraps, also makes the
+// operation panic with the trapped conditions it raised. A Condition
+// implements the error interface, so callers that want an error instead of
+// a panic can check the recorded conditions:
+//
+//	if c := z.Conditions() & (Overflow | DivisionByZero); c != 0 {
+//		return c
+//	}
+type Condition uint16
+
+// The following conditions are supported.
+const (
+	// Inexact is raised when a result was rounded and the digits that were
+	// discarded were not all zero.
+	Inexact Condition = 1 << iota
+
+	// Rounded is raised when a result was rounded, even if only zeros were
+	// discarded.
+	Rounded
+
+	// Overflow is raised when a result's exponent is too large to be
+	// represented. The result is ±Inf.
+	Overflow
+
+	// Underflow is raised when a result's exponent is too small to be
+	// represented. The result is ±0.
+	Underflow
+
+	// DivisionByZero is raised when a finite, nonzero number is divided by
+	// zero. The result is ±Inf.
+	DivisionByZero
+
+	// InvalidOperation is raised when an operation has no sensible result
+	// or an operand is a signaling NaN. The result is NaN.
+	InvalidOperation
+
+	// Clamped is raised when a result's exponent was changed to fit the
+	// Context's exponent limits without changing its value, or when a
+	// result underflowed to zero.
+	Clamped
+
+	// Subnormal is raised when a result is nonzero and its adjusted exponent
+	// is less than the Context's Emin.
+	Subnormal
+)
+
+var conditions = [...]string{
+	"Inexact",
+	"Rounded",
+	"Overflow",
+	"Underflow",
+	"DivisionByZero",
+	"InvalidOperation",
+	"Clamped",
+	"Subnormal",
+}
+
+// Error implements the error interface.
+func (c Condition) Error() string {
+	return "decimal: " + c.String()
+}
+
+// String returns the names of the conditions in c, separated by ", ".
+func (c Condition) String() string {
+	if c == 0 {
+		return "None"
+	}
+	var names []string
+	for i, name := range conditions {
+		if c&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	if c >= 1<<uint(len(conditions)) {
+		names = append(names, "Unknown")
+	}
+	return strings.Join(names, ", ")
+}
+
+// Conditions returns the conditions raised in x's Context.
+func (x *Big) Conditions() Condition {
+	return x.ctx.Conditions
+}
+
+// SetConditions sets the conditions in z's Context to c and returns z.
+// z.SetConditions(0) clears them.
+func (z *Big) SetConditions(c Condition) *Big {
+	z.ctx.Conditions = c
+	return z
+}
+
+// SetTraps sets the conditions trapped by z's Context to c and returns z.
+// z.SetTraps(0) records every condition without panicking, except that the
+// Unneeded RoundingMode always traps Inexact.
+func (z *Big) SetTraps(c Condition) *Big {
+	z.ctx.Traps = c
+	return z
+}
+
+// raise adds c to the conditions in z's Context and returns z. If any of
+// them are trapped it panics with the trapped ones.
+func (z *Big) raise(c Condition) *Big {
+	z.ctx.Conditions |= c
+	if t := c & z.ctx.trapped(); t != 0 {
+		panic(t)
+	}
+	return z
+}
+
+// overflow sets z to -Inf if neg is true or +Inf otherwise, raises Overflow,
+// Inexact and Rounded, and returns z. If z's Context has exponent limits and
+// its RoundingMode rounds toward zero in z's direction, z is set to the
+// finite value with the largest magnitude instead.
+func (z *Big) overflow(neg bool) *Big {
+	// The largest finite value's last digit is 9.
+	if z.ctx.limited() && z.ctx.Mode != Unneeded &&
+		!z.ctx.Mode.needsInc(1, !neg, 9) {
+		z.setMax(neg)
+	} else {
+		z.SetInf(neg)
+	}
+	return z.raise(Overflow | Inexact | Rounded)
+}
+
+// underflow sets z to -0 if neg is true or +0 otherwise, raises Underflow,
+// Inexact and Rounded, and returns z. If z's Context has exponent limits, the
+// nonzero value that underflowed is rounded to a multiple of 10 ** Etiny
+// instead, so z is either zero or the subnormal value with the smallest
+// magnitude, and Subnormal (and Clamped if z is zero) is raised as well.
+func (z *Big) underflow(neg bool) *Big {
+	if !z.ctx.limited() {
+		return z.setZero(neg).raise(Underflow | Inexact | Rounded)
+	}
+	// The discarded digits are less than half a unit and the digit before
+	// them is zero.
+	scale := int32(-z.ctx.etiny())
+	if z.ctx.Mode != Unneeded && z.ctx.Mode.needsInc(-1, !neg, 0) {
+		v := int64(1)
+		if neg {
+			v = -1
+		}
+		return z.SetMantScale(v, scale).raise(Underflow | Subnormal | Inexact | Rounded)
+	}
+	z.setZero(neg)
+	z.scale = scale
+	return z.raise(Underflow | Subnormal | Inexact | Rounded | Clamped)
+}
@@ -1,8 +1,11 @@
 package decimal
 
 import (
+	"fmt"
 	"math"
 	"math/big"
+	"strconv"
+	"strings"
 )
 
 // Precision and scale limits.
@@ -19,45 +22,224 @@ const (
 const DefaultPrec = 16
 
 // Context tells the lossy arithmetic operations how to do their jobs.
-// Precision is the maximum number of digits to be used for the decimal. Mode
-// instructs lossy operations how to round.
+//
+// Emax and Emin limit the adjusted exponent of a result, that is, its
+// exponent when it's written with one digit before the radix. Results that
+// are too large overflow, and results that are too small become subnormal,
+// with fewer digits of precision, or underflow to zero. If Clamp is true the
+// exponent of a result with fewer than Precision digits is also limited to
+// Emax - Precision + 1, as in the IEEE 754-2008 interchange formats. The
+// limits apply to the results of operations that round to the Context's
+// precision, like Quo, and to those of Add, Sub and Mul if RoundAll is true.
+// A Context whose Emax and Emin are both zero has no
+// exponent limits other than those imposed by a Big's int32 scale.
+//
+// A Context's text form, used by MarshalText and UnmarshalText, lists its
+// settings as comma-separated key: value pairs, for example
+//
+//	precision: 34, rounding: ToNearestEven, emax: 6144, emin: -6143, clamp: true
+//
+// The rounding key holds a RoundingMode's name, and a scale key makes the
+// Context's scale fixed. The rounding key and one of the precision and scale
+// keys are required. Conditions and Traps are not part of the text form.
 type Context struct {
-	precision int32
-	mode      RoundingMode
+	// Prec is the maximum number of digits to be used for the decimal. If
+	// it's zero DefaultPrec is used, and if it's negative the precision is
+	// unlimited.
+	Prec int32
+
+	// Mode instructs lossy operations how to round.
+	Mode RoundingMode
+
+	// Conditions records the exceptional conditions raised by operations.
+	Conditions Condition
+
+	// Traps lists the conditions that make an operation panic.
+	Traps Condition
+
+	// Emax is the maximum adjusted exponent.
+	Emax int32
+
+	// Emin is the minimum adjusted exponent of a normal value.
+	Emin int32
+
+	// Clamp reports whether exponents are clamped to Emax - Precision + 1.
+	Clamp bool
+
+	// RoundAll reports whether Add, Sub and Mul round their results to Prec
+	// digits and the exponent limits, as the General Decimal Arithmetic
+	// specification requires. Otherwise their results are exact, which is
+	// the default.
+	RoundAll bool
+
+	// Fixed reports whether Add, Sub, Mul and Quo round their results to
+	// Scale digits after the radix, as in accounting, instead of using Prec
+	// and the exponent limits. Results with fewer digits after the radix
+	// are padded with zeros.
+	Fixed bool
+
+	// Scale is the scale of results if Fixed is true.
+	Scale int32
+}
+
+// NewContext returns a Context with the given precision and RoundingMode and
+// no exponent limits.
+func NewContext(prec int32, mode RoundingMode) Context {
+	return Context{Prec: prec, Mode: mode}
+}
+
+// NewFixedContext returns a Context with a fixed scale and the given
+// RoundingMode.
+func NewFixedContext(scale int32, mode RoundingMode) Context {
+	return Context{Mode: mode, Fixed: true, Scale: scale}
+}
+
+// MarshalText implements encoding.TextMarshaler. The exponent limits are
+// omitted if c has none, as are clamp and roundall if they're false and
+// scale if c doesn't have a fixed scale.
+func (c Context) MarshalText() ([]byte, error) {
+	mode, err := c.Mode.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	b := []byte("precision: ")
+	b = strconv.AppendInt(b, int64(c.Prec), 10)
+	b = append(b, ", rounding: "...)
+	b = append(b, mode...)
+	if c.Emax != 0 || c.Emin != 0 {
+		b = append(b, ", emax: "...)
+		b = strconv.AppendInt(b, int64(c.Emax), 10)
+		b = append(b, ", emin: "...)
+		b = strconv.AppendInt(b, int64(c.Emin), 10)
+	}
+	if c.Clamp {
+		b = append(b, ", clamp: true"...)
+	}
+	if c.RoundAll {
+		b = append(b, ", roundall: true"...)
+	}
+	if c.Fixed {
+		b = append(b, ", scale: "...)
+		b = strconv.AppendInt(b, int64(c.Scale), 10)
+	}
+	return b, nil
 }
 
-// Precision returns c's precision.
+// UnmarshalText implements encoding.TextUnmarshaler. It sets c's settings to
+// those in text, which must be in the form written by MarshalText, but
+// leaves c's Conditions and Traps alone.
+func (c *Context) UnmarshalText(text []byte) error {
+	ctx := Context{Conditions: c.Conditions, Traps: c.Traps}
+	var prec, mode bool
+	for _, pair := range strings.Split(string(text), ",") {
+		i := strings.IndexByte(pair, ':')
+		if i < 0 {
+			return fmt.Errorf("decimal: invalid Context setting %q", pair)
+		}
+		key := strings.TrimSpace(pair[:i])
+		val := strings.TrimSpace(pair[i+1:])
+
+		var err error
+		switch key {
+		case "precision":
+			ctx.Prec, err = parseInt32(val)
+			prec = true
+		case "rounding":
+			ctx.Mode, err = ParseRoundingMode(val)
+			mode = true
+		case "emax":
+			ctx.Emax, err = parseInt32(val)
+		case "emin":
+			ctx.Emin, err = parseInt32(val)
+		case "clamp":
+			ctx.Clamp, err = strconv.ParseBool(val)
+		case "roundall":
+			ctx.RoundAll, err = strconv.ParseBool(val)
+		case "scale":
+			ctx.Scale, err = parseInt32(val)
+			ctx.Fixed = true
+		default:
+			return fmt.Errorf("decimal: unknown Context setting %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("decimal: invalid Context setting %q: %v", key, err)
+		}
+	}
+	if (!prec && !ctx.Fixed) || !mode {
+		return fmt.Errorf("decimal: Context %q must set rounding and precision or scale", text)
+	}
+	*c = ctx
+	return nil
+}
+
+// parseInt32 parses s as a base 10 int32.
+func parseInt32(s string) (int32, error) {
+	n, err := strconv.ParseInt(s, 10, 32)
+	return int32(n), err
+}
+
+// limited reports whether c has exponent limits and a limited precision.
+func (c Context) limited() bool {
+	return (c.Emax != 0 || c.Emin != 0) && c.prec() != 0
+}
+
+// etiny returns the smallest exponent of a subnormal value in c.
+func (c Context) etiny() int64 {
+	return int64(c.Emin) - int64(c.prec()) + 1
+}
+
+// etop returns the largest exponent of a value with c's full precision.
+func (c Context) etop() int64 {
+	return int64(c.Emax) - int64(c.prec()) + 1
+}
+
+// trapped returns the conditions that panic when raised in c. Unneeded
+// always traps Inexact.
+func (c Context) trapped() Condition {
+	if c.Mode == Unneeded {
+		return c.Traps | Inexact
+	}
+	return c.Traps
+}
+
+// Precision returns c's precision: DefaultPrec if c.Prec is zero, 0 if it's
+// negative, and c.Prec otherwise.
 func (c Context) Precision() int32 {
 	return c.prec()
 }
 
 func (c Context) prec() int32 {
-	if c.precision == 0 {
+	if c.Prec == 0 {
 		return DefaultPrec
 	}
-	if c.precision < 0 {
+	if c.Prec < 0 {
 		return 0
 	}
-	return c.precision
+	return c.Prec
 }
 
 // The following are called ContextXX instead of DecimalXX
 // to reserve the DecimalXX namespace for future decimal types.
 //
-// The following Contexts are based on IEEE 754R. Context is exported for this
-// documentation but is not expected to be used itself.
+// The following Contexts are based on IEEE 754R.
 var (
 	// Context32 is the IEEE 754R Decimal32 format.
-	// It has a precision of 7 and mode of ToNearestEven.
-	Context32 = Context{precision: 7, mode: ToNearestEven}
+	// It has a precision of 7, mode of ToNearestEven, Emax of 96, Emin of
+	// -95, and clamps exponents.
+	Context32 = Context{Prec: 7, Mode: ToNearestEven,
+		Emax: 96, Emin: -95, Clamp: true}
 
 	// Context64 is the IEEE 754R Decimal64 format.
-	// It has a precision of 16 and mode of ToNearestEven.
-	Context64 = Context{precision: 16, mode: ToNearestEven}
+	// It has a precision of 16, mode of ToNearestEven, Emax of 384, Emin of
+	// -383, and clamps exponents.
+	Context64 = Context{Prec: 16, Mode: ToNearestEven,
+		Emax: 384, Emin: -383, Clamp: true}
 
 	// Context128 is the IEEE 754R Decimal128 format.
-	// It has a precision of 34 and mode of ToNearestEven.
-	Context128 = Context{precision: 34, mode: ToNearestEven}
+	// It has a precision of 34, mode of ToNearestEven, Emax of 6144, Emin of
+	// -6143, and clamps exponents.
+	Context128 = Context{Prec: 34, Mode: ToNearestEven,
+		Emax: 6144, Emin: -6143, Clamp: true}
 )
 
 // RoundingMode determines how a Decimal will be rounded
@@ -73,19 +255,70 @@ const (
 	ToNegativeInf                     // == IEEE 754-2008 roundTowardNegative
 	ToPositiveInf                     // == IEEE 754-2008 roundTowardPositive
 
-	// Unneeded means finite decimal expansion. Will panic if this
-	// RoundingMode is provided and the lossy operation does not have a finite
-	// decimal expansion.
+	// Unneeded means finite decimal expansion. Will panic with Inexact if
+	// this RoundingMode is provided and the lossy operation does not have a
+	// finite decimal expansion, as if Inexact were trapped.
 	Unneeded
+
+	// ToNearestZero rounds to the nearest value, breaking ties toward zero.
+	// It's also known as half-down: Python's ROUND_HALF_DOWN and Java's
+	// RoundingMode.HALF_DOWN.
+	ToNearestZero
+
+	// AwayFromZero05 rounds toward zero, unless the last digit of the
+	// result would be 0 or 5, in which case it rounds away from zero. It's
+	// Python's ROUND_05UP, which allows a result to be rounded again to a
+	// shorter precision without double rounding errors.
+	AwayFromZero05
+)
+
+// Ceiling and Floor are aliases for ToPositiveInf and ToNegativeInf.
+const (
+	Ceiling = ToPositiveInf
+	Floor   = ToNegativeInf
 )
 
 //go:generate stringer -type RoundingMode
 
+// ParseRoundingMode returns the RoundingMode named s, as returned by its
+// String method.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	for r := RoundingMode(0); r < RoundingMode(len(_RoundingMode_index)-1); r++ {
+		if r.String() == s {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("decimal: unknown RoundingMode %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns r's name.
+func (r RoundingMode) MarshalText() ([]byte, error) {
+	if r >= RoundingMode(len(_RoundingMode_index)-1) {
+		return nil, fmt.Errorf("decimal: unknown RoundingMode %d", r)
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It sets r to the
+// RoundingMode named by text.
+func (r *RoundingMode) UnmarshalText(text []byte) error {
+	m, err := ParseRoundingMode(string(text))
+	if err != nil {
+		return err
+	}
+	*r = m
+	return nil
+}
+
 var (
 	ptFive = New(5, 1)
 	one    = New(1, 0)
 	two    = New(2, 0)
-	max64  = New(math.MaxInt64, 0)
+	five   = New(5, 0)
+
+	sqrt2    = New(14142135623730950, 16)
+	invSqrt2 = New(7071067811865475, 16)
+	sqrt10   = New(31622776601683793, 16)
 
 	oneInt = big.NewInt(1)
 	twoInt = big.NewInt(2)
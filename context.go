@@ -0,0 +1,130 @@
+package decimal
+
+// The following methods apply a Context to an operation. Each one sets z's
+// Context to c, performs the operation with it, and returns z, so a single
+// Context can govern a calculation regardless of the Contexts of its
+// operands or intermediate values:
+//
+//	ctx := Context128
+//	ctx.Quo(z, x, y)
+//	ctx.Add(z, z, u)
+//
+// The conditions raised by each operation are added to c's Conditions, even
+// if one of them is trapped, so c records every condition raised during the
+// calculation. Since z's Context is set to c, z's Conditions include those
+// raised by the earlier operations as well. The operands' Contexts are never
+// used.
+
+// record adds the conditions recorded in z's Context to c's.
+func (c *Context) record(z *Big) {
+	c.Conditions |= z.ctx.Conditions
+}
+
+// Abs sets z to |x| using c and returns z.
+func (c *Context) Abs(z, x *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Abs(x)
+}
+
+// Add sets z to x + y using c and returns z.
+func (c *Context) Add(z, x, y *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Add(x, y)
+}
+
+// Exp sets z to e ** x rounded to c and returns z.
+func (c *Context) Exp(z, x *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Exp(x)
+}
+
+// FMA sets z to (x * y) + u rounded once to c and returns z.
+func (c *Context) FMA(z, x, y, u *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).FMA(x, y, u)
+}
+
+// Log sets z to the natural logarithm of x rounded to c and returns z.
+func (c *Context) Log(z, x *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Log(x)
+}
+
+// Log10 sets z to the base-10 logarithm of x rounded to c and returns z.
+func (c *Context) Log10(z, x *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Log10(x)
+}
+
+// Mul sets z to x * y using c and returns z.
+func (c *Context) Mul(z, x, y *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Mul(x, y)
+}
+
+// Neg sets z to -x using c and returns z.
+func (c *Context) Neg(z, x *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Neg(x)
+}
+
+// Pow sets z to x ** y rounded to c and returns z.
+func (c *Context) Pow(z, x, y *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Pow(x, y)
+}
+
+// Quantize sets z to x with the given scale, rounded with c's RoundingMode,
+// and returns z.
+func (c *Context) Quantize(z, x *Big, scale int32) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Quantize(x, scale)
+}
+
+// Quo sets z to x / y rounded to c and returns z.
+func (c *Context) Quo(z, x, y *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Quo(x, y)
+}
+
+// QuoInt sets z to x / y truncated toward zero using c and returns z.
+func (c *Context) QuoInt(z, x, y *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).QuoInt(x, y)
+}
+
+// Rem sets z to the remainder of x / y using c and returns z.
+func (c *Context) Rem(z, x, y *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Rem(x, y)
+}
+
+// Round sets z to x rounded to c's precision with c's RoundingMode and
+// returns z. If c has exponent limits, x is also brought into c's exponent
+// range, which may overflow, underflow, or clamp its exponent. If c has a
+// fixed scale, x is rounded or padded to it instead.
+func (c *Context) Round(z, x *Big) *Big {
+	defer c.record(z)
+	z.SetContext(*c)
+	if z.checkNaNs(x) {
+		return z
+	}
+	z.Set(x)
+	z.ctx = *c
+	if c.Fixed {
+		return z.fixScale()
+	}
+	return z.fix()
+}
+
+// Sqrt sets z to the square root of x rounded to c and returns z.
+func (c *Context) Sqrt(z, x *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Sqrt(x)
+}
+
+// Sub sets z to x - y using c and returns z.
+func (c *Context) Sub(z, x, y *Big) *Big {
+	defer c.record(z)
+	return z.SetContext(*c).Sub(x, y)
+}
@@ -33,7 +33,7 @@ type Big struct {
 	compact int64
 	scale   int32
 	ctx     Context
-	form    form // zero, finite, or inf.
+	form    form // zero, finite, inf, qnan, or snan.
 
 	// If the mantissa is not stored in the compact field, it's held here.
 	mantissa big.Int
@@ -48,10 +48,14 @@ const (
 	zero = iota
 	finite
 	inf
+	qnan // quiet NaN
+	snan // signaling NaN
 )
 
-// An ErrNaN panic is raised by a Decimal operation that would lead to a NaN
-// under IEEE-754 rules. An ErrNaN implements the error interface.
+// An ErrNaN panic is raised by an operation that has a NaN operand but can't
+// return a NaN, like Cmp. Operations that can return a NaN, like Add or Quo,
+// set their result to a quiet NaN instead. An ErrNaN implements the error
+// interface.
 type ErrNaN struct {
 	msg string
 }
@@ -79,24 +83,37 @@ func New(value int64, scale int32) *Big {
 	return new(Big).SetMantScale(value, scale)
 }
 
-// Abs sets z to the absolute value of x if x is finite and returns z.
+// Abs sets z to the absolute value of x and returns z.
 func (z *Big) Abs(x *Big) *Big {
+	if z.checkNaNs(x) {
+		return z
+	}
 	if x.form != finite {
+		ctx := z.ctx
+		z.Set(x)
+		z.ctx = ctx
+		z.compact = arith.Abs(z.compact)
 		return z
 	}
 	if x.isCompact() {
 		z.compact = arith.Abs(x.compact)
 	} else {
 		z.mantissa.Abs(&x.mantissa)
+		z.compact = c.Inflated
 	}
 	z.scale = x.scale
-	z.ctx = x.ctx
 	z.form = finite
 	return z
 }
 
-// Add sets z to x + y and returns z.
+// Add sets z to x + y and returns z. The sum is exact unless z's Context has
+// a fixed scale or RoundAll is true.
 func (z *Big) Add(x, y *Big) *Big {
+	return z.add(x, y).finish()
+}
+
+// add sets z to x + y exactly and returns z.
+func (z *Big) add(x, y *Big) *Big {
 	if x.form == finite && y.form == finite {
 		z.form = finite
 		if x.isCompact() {
@@ -111,29 +128,39 @@ func (z *Big) Add(x, y *Big) *Big {
 		return z.addBig(x, y)
 	}
 
+	if z.checkNaNs(x, y) {
+		return z
+	}
+
 	if x.form == inf && y.form == inf &&
 		x.SignBit() != y.SignBit() {
 		// +Inf + -Inf
 		// -Inf + +Inf
-		z.form = zero
-		panic(ErrNaN{"addition of infinities with opposing signs"})
+		return z.setNaN(AddInfSubInf)
 	}
 
 	if x.form == zero && y.form == zero {
 		// ±0 + ±0
-		z.form = zero
-		return z
+		neg := x.SignBit()
+		if neg != y.SignBit() {
+			neg = z.ctx.Mode == ToNegativeInf
+		}
+		return z.setZero(neg)
 	}
 
+	// Keep z's Context.
+	ctx := z.ctx
 	if x.form == inf || y.form == zero {
 		// ±Inf + y
 		// x + ±0
-		return z.Set(x)
+		z.Set(x)
+	} else {
+		// ±0 + y
+		// x + ±Inf
+		z.Set(y)
 	}
-
-	// ±0 + y
-	// x + ±Inf
-	return z.Set(y)
+	z.ctx = ctx
+	return z
 }
 
 // addCompact sets z to x + y and returns z.
@@ -146,13 +173,13 @@ func (z *Big) addCompact(x, y *Big) *Big {
 		if ok {
 			z.compact = sum
 			if sum == 0 {
-				z.form = zero
+				z.setZero(z.ctx.Mode == ToNegativeInf)
 			}
 		} else {
 			z.mantissa.Add(big.NewInt(x.compact), big.NewInt(y.compact))
 			z.compact = c.Inflated
 			if z.mantissa.Sign() == 0 {
-				z.form = zero
+				z.setZero(z.ctx.Mode == ToNegativeInf)
 			}
 		}
 		return z
@@ -174,6 +201,9 @@ func (z *Big) addCompact(x, y *Big) *Big {
 		sum, ok := checked.Add(hi.compact, scaledLo)
 		if ok {
 			z.compact = sum
+			if sum == 0 {
+				z.setZero(z.ctx.Mode == ToNegativeInf)
+			}
 			return z
 		}
 	}
@@ -181,7 +211,7 @@ func (z *Big) addCompact(x, y *Big) *Big {
 	z.mantissa.Add(scaled, big.NewInt(hi.compact))
 	z.compact = c.Inflated
 	if z.mantissa.Sign() == 0 {
-		z.form = zero
+		z.setZero(z.ctx.Mode == ToNegativeInf)
 	}
 	return z
 }
@@ -197,7 +227,7 @@ func (z *Big) addHalf(comp, non *Big) *Big {
 		z.scale = comp.scale
 		z.compact = c.Inflated
 		if z.mantissa.Sign() == 0 {
-			z.form = zero
+			z.setZero(z.ctx.Mode == ToNegativeInf)
 		}
 		return z
 	}
@@ -217,12 +247,12 @@ func (z *Big) addBig(x, y *Big) *Big {
 	}
 
 	inc := hi.scale - lo.scale
-	scaled := checked.MulBigPow10(&lo.mantissa, inc)
+	scaled := checked.MulBigPow10(new(big.Int).Set(&lo.mantissa), inc)
 	z.mantissa.Add(&hi.mantissa, scaled)
 	z.compact = c.Inflated
 	z.scale = hi.scale
 	if z.mantissa.Sign() == 0 {
-		z.form = zero
+		z.setZero(z.ctx.Mode == ToNegativeInf)
 	}
 	return z
 }
@@ -247,129 +277,189 @@ func (x *Big) BitLen() int {
 	return x.mantissa.BitLen()
 }
 
-// Cmp compares d and x and returns:
+// Class returns the class of x, which is one of "sNaN", "NaN", "-Infinity",
+// "-Normal", "-Subnormal", "-Zero", "+Zero", "+Subnormal", "+Normal", or
+// "+Infinity". Whether x is subnormal depends on x's Context.
+func (x *Big) Class() string {
+	var class string
+	switch {
+	case x.form == snan:
+		return "sNaN"
+	case x.form == qnan:
+		return "NaN"
+	case x.form == inf:
+		class = "Infinity"
+	case x.isZero():
+		class = "Zero"
+	case x.IsSubnormal():
+		class = "Subnormal"
+	default:
+		class = "Normal"
+	}
+	if x.SignBit() {
+		return "-" + class
+	}
+	return "+" + class
+}
+
+// Cmp compares x and y and returns:
 //
-//   -1 if z <  x
-//    0 if z == x
-//   +1 if z >  x
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
 //
-// It does not modify d or x.
-func (z *Big) Cmp(x *Big) int {
+// It does not modify x or y, and it does not allocate. Cmp panics with
+// ErrNaN if x or y is NaN.
+func (x *Big) Cmp(y *Big) int {
+	if x.IsNaN() || y.IsNaN() {
+		panic(ErrNaN{"comparison with NaN"})
+	}
+
 	// Check for same pointers.
-	if z == x {
+	if x == y {
 		return 0
 	}
 
-	// Same scales means we can compare straight across.
-	if z.scale == x.scale {
-		if z.isCompact() && x.isCompact() {
-			if z.compact > x.compact {
-				return +1
-			}
-			if z.compact < x.compact {
-				return -1
-			}
-			return 0
-		}
-		if z.isInflated() && x.isInflated() {
-			if z.mantissa.Sign() != x.mantissa.Sign() {
-				return z.mantissa.Sign()
-			}
-
-			if z.scale < 0 {
-				return z.mantissa.Cmp(&x.mantissa)
-			}
-
-			zb := z.mantissa.Bits()
-			xb := x.mantissa.Bits()
-
-			min := len(zb)
-			if len(xb) < len(zb) {
-				min = len(xb)
-			}
-			i := 0
-			for i < min-1 && zb[i] == xb[i] {
-				i++
-			}
-			if zb[i] > xb[i] {
-				return +1
-			}
-			if zb[i] < xb[i] {
-				return -1
-			}
-			return 0
+	if x.form == inf || y.form == inf {
+		xi, yi := infSign(x), infSign(y)
+		switch {
+		case xi > yi:
+			return +1
+		case xi < yi:
+			return -1
 		}
+		return 0
 	}
 
-	// Different scales -- check signs and/or if they're
-	// both zero.
-
-	ds := z.Sign()
-	xs := x.Sign()
+	xs, ys := x.Sign(), y.Sign()
 	switch {
-	case ds > xs:
+	case xs > ys:
 		return +1
-	case ds < xs:
+	case xs < ys:
 		return -1
-	case ds == 0 && xs == 0:
+	case xs == 0:
 		return 0
 	}
+	return xs * cmpAbs(x, y)
+}
 
-	// Scales aren't equal, the signs are the same, and both
-	// are non-zero.
-	dl := int32(z.Prec()) - z.scale
-	xl := int32(x.Prec()) - x.scale
-	if dl > xl {
-		return +1
+// CmpAbs compares |x| and |y|. See Cmp for more information.
+func (x *Big) CmpAbs(y *Big) int {
+	if x.IsNaN() || y.IsNaN() {
+		panic(ErrNaN{"comparison with NaN"})
 	}
-	if dl < xl {
-		return -1
+	if x == y {
+		return 0
 	}
+	if x.form == inf || y.form == inf {
+		return b2i(x.form == inf) - b2i(y.form == inf)
+	}
+	xz, yz := x.Sign() == 0, y.Sign() == 0
+	if xz || yz {
+		return b2i(!xz) - b2i(!yz)
+	}
+	return cmpAbs(x, y)
+}
 
-	// We need to inflate one of the numbers.
-
-	dc := z.compact // hi
-	xc := x.compact // lo
-
-	var swap bool
+// CmpFloat64 compares x and f. The comparison is exact: f is treated as the
+// decimal value it represents, so x.CmpFloat64(0.1) is -1 if x is 0.1, since
+// the float64 0.1 is slightly larger. CmpFloat64 panics with ErrNaN if f is
+// NaN.
+func (x *Big) CmpFloat64(f float64) int {
+	switch {
+	case math.IsNaN(f):
+		panic(ErrNaN{"comparison with NaN"})
+	case math.IsInf(f, 0):
+		var t Big
+		return x.Cmp(t.SetInf(f < 0))
+	case f > -(1<<63) && f < 1<<63 && f == math.Trunc(f):
+		return x.CmpInt64(int64(f))
+	}
+	var t Big
+	return x.Cmp(t.setFloat64Exact(f))
+}
 
-	hi, lo := z, x
-	if hi.scale < lo.scale {
-		hi, lo = lo, hi
-		dc, xc = xc, dc
-		swap = true // d is lo
-	}
-
-	diff := hi.scale - lo.scale
-	if diff <= c.BadScale {
-		var ok bool
-		xc, ok = checked.MulPow10(xc, diff)
-		if !ok && dc == c.Inflated {
-			// d is lo
-			if swap {
-				zm := new(big.Int).Set(&z.mantissa)
-				return checked.MulBigPow10(zm, diff).Cmp(&x.mantissa)
-			}
-			// x is lo
-			xm := new(big.Int).Set(&x.mantissa)
-			return z.mantissa.Cmp(checked.MulBigPow10(xm, diff))
+// CmpInt64 compares x and v. See Cmp for more information.
+func (x *Big) CmpInt64(v int64) int {
+	if x.form == finite && x.isCompact() && x.scale == 0 {
+		switch {
+		case x.compact > v:
+			return +1
+		case x.compact < v:
+			return -1
 		}
+		return 0
 	}
+	var t Big
+	return x.Cmp(t.SetMantScale(v, 0))
+}
 
-	if swap {
-		dc, xc = xc, dc
+// CmpTotal compares x and y using the total ordering from IEEE 754-2008.
+// Unlike Cmp, it distinguishes values that are numerically equal but have
+// different scales: of two equal positive values, the one with the larger
+// scale is smaller, and of two equal negative values, the one with the
+// larger scale is larger. For example, 1.00 < 1.0 < 1 and -1 < -1.0 < -1.00.
+// Likewise, -0 < +0.
+//
+// NaN values are ordered above +Inf, signaling NaNs below quiet NaNs, and
+// NaNs of the same kind by their payloads.
+func (x *Big) CmpTotal(y *Big) int {
+	if x.IsNaN() || y.IsNaN() {
+		// Rank each value: non-NaN, signaling NaN, then quiet NaN.
+		xr, yr := b2i(x.IsNaN())+b2i(x.form == qnan), b2i(y.IsNaN())+b2i(y.form == qnan)
+		switch {
+		case xr > yr:
+			return +1
+		case xr < yr:
+			return -1
+		case x.Payload() > y.Payload():
+			return +1
+		case x.Payload() < y.Payload():
+			return -1
+		}
+		return 0
 	}
-
-	if dc != c.Inflated {
-		if xc != c.Inflated {
-			return arith.AbsCmp(dc, xc)
+	if r := x.Cmp(y); r != 0 {
+		return r
+	}
+	if xn, yn := x.SignBit(), y.SignBit(); xn != yn {
+		// -0 and +0
+		if xn {
+			return -1
 		}
-		return big.NewInt(dc).Cmp(&x.mantissa)
+		return +1
 	}
-	if xc != c.Inflated {
-		return z.mantissa.Cmp(big.NewInt(xc))
+	if x.form == inf || x.scale == y.scale {
+		return 0
+	}
+	r := +1
+	if x.scale > y.scale {
+		r = -1
 	}
-	return z.mantissa.Cmp(&x.mantissa)
+	if x.SignBit() {
+		r = -r
+	}
+	return r
+}
+
+// CopySign sets z to x with the sign of y and returns z.
+func (z *Big) CopySign(x, y *Big) *Big {
+	neg := y.SignBit()
+	switch x.form {
+	case qnan, snan:
+		z.SetNaN(x.form == snan, x.Payload())
+	case zero:
+		z.setZero(neg)
+		z.scale = x.scale
+	case inf:
+		z.SetInf(neg)
+	default:
+		z.Abs(x)
+		if neg {
+			z.Neg(z)
+		}
+	}
+	return z
 }
 
 // Context returns x's Context.
@@ -377,45 +467,71 @@ func (x *Big) Context() Context {
 	return x.ctx
 }
 
-// Exp sets z to e ** x and returns z.
-/*func (z *Big) Exp(x *Big) *Big {
-	if x.form == zero {
+// Equal returns true if x == y. Unlike Cmp, it doesn't panic if x or y is
+// NaN: as in IEEE 754, a NaN is not equal to anything, including itself.
+func (x *Big) Equal(y *Big) bool {
+	return !x.IsNaN() && !y.IsNaN() && x.Cmp(y) == 0
+}
+
+// Exp sets z to e ** x and returns z. The result is correctly rounded to
+// z's precision using z's RoundingMode. If z's precision is unlimited the
+// result is rounded to DefaultPrec digits instead, since e ** x is only exact
+// if x is zero.
+func (z *Big) Exp(x *Big) *Big {
+	if z.checkNaNs(x) {
+		return z
+	}
+	if x.form == inf {
+		if x.SignBit() {
+			// e ** -Inf == 0
+			return z.setZero(false)
+		}
+		// e ** +Inf == +Inf
+		return z.SetInf(false)
+	}
+	if x.ez() {
 		// e ** 0 == 1
 		return z.SetMantScale(1, 0)
 	}
-	if x.SignBit() {
-		// 1 / (e ** -x)
-		return z.Quo(one, z.Exp(z.Neg(x)))
+
+	// e ** x overflows (or underflows) the range of a Big if |x| >= 1e10.
+	if x.adjusted() >= 10 {
+		if x.SignBit() {
+			return z.underflow(false)
+		}
+		return z.overflow(false)
 	}
-	intg, frac := new(Big).Modf(x)
-	if intg.form == zero {
-		return frac.taylor(x)
+
+	// If |x| < 10 ** -(zp+1) then e ** x rounds the same as 1 + x, which
+	// in turn rounds the same as 1 ± 10 ** -(zp+2).
+	zp := z.zivPrec()
+	if x.adjusted() < -int64(zp)-1 {
+		m := checked.MulBigPow10(big.NewInt(1), zp+2)
+		m.Add(m, big.NewInt(int64(x.Sign())))
+		return z.SetBigMantScale(m, zp+2).roundToPrec(zp)
 	}
+	return z.ziv(x, (*Big).exp)
+}
 
-	// n = e ** (1 + frac / integ)
-	n := new(Big).taylor(z.Add(z.Quo(frac, intg), one))
+// FMA sets z to (x * y) + u and returns z. The result is computed with only
+// one rounding to z's precision using z's RoundingMode.
+func (z *Big) FMA(x, y, u *Big) *Big {
+	if z.checkNaNs(x, y, u) {
+		return z
+	}
 
-	r := New(1, 0)
-	zp := z.ctx.prec()
-	var tmp Big
-	for intg.Cmp(max64) >= 0 {
-		r.Mul(r, tmp.powInt(n, math.MaxInt64)).Round(zp)
-		frac.Sub(frac, max64)
-	}
-	return z.Mul(r, n.powInt(n, intg.compact)).Round(zp)
-}*/
-
-// Format implements the fmt.Formatter interface.
-// func (z *Big) Format(s fmt.State, r rune) {
-// 	switch r {
-// 	case 'e', 'g', 's', 'f':
-// 		s.Write([]byte(z.String()))
-// 	case 'E':
-// 		s.Write([]byte(z.toString(true, upper)))
-// 	default:
-// 		fmt.Fprint(s, *z)
-// 	}
-// }
+	// mul and add are exact, so the only rounding is done by fix. mul
+	// raises its conditions, like InvalidOperation for Inf * 0, in t's
+	// Context, so they're raised in z's as well.
+	var t Big
+	t.mul(x, y)
+	z.raise(t.ctx.Conditions)
+	z.add(&t, u)
+	if z.ctx.Fixed {
+		return z.fixScale()
+	}
+	return z.fix()
+}
 
 // IsBig returns true if x, with its fractional part truncated, cannot fit
 // inside an int64.
@@ -444,12 +560,12 @@ func (x *Big) Int() *big.Int {
 
 // Int64 returns x as an int64, truncating the fractional portion, if any.
 func (x *Big) Int64() int64 {
-	var b int64
-	if x.isCompact() {
-		b = x.compact
-	} else {
-		b = x.mantissa.Int64()
+	if x.isInflated() {
+		// The mantissa might not fit into an int64 even if x's integral
+		// part does.
+		return x.Int().Int64()
 	}
+	b := x.compact
 	if x.scale == 0 {
 		return b
 	}
@@ -485,31 +601,126 @@ func (x *Big) IsInt() bool {
 	if x.form != finite {
 		return x.form == zero
 	}
-	// Prec doesn't count trailing zeros,
-	// so precision <= scale means scale is all
-	// trailing zeros.
-	// E.g., 12.000:    scale == 3, prec == 2
-	//       1234.0000: scale == 4, prec == 4
-	return x.scale <= 0 || x.Prec() < int(x.scale)
+	if x.scale <= 0 {
+		return true
+	}
+	// x is an integer iff its last scale digits are all zero.
+	// E.g., 12.000:    mantissa == 12000, scale == 3
+	//       1234.0001: mantissa == 12340001, scale == 4
+	if x.Prec() <= int(x.scale) {
+		return false
+	}
+	if x.isCompact() {
+		p, _ := pow.Ten64(int64(x.scale))
+		return x.compact%p == 0
+	}
+	p := pow.BigTen(int64(x.scale))
+	var r big.Int
+	return r.Rem(&x.mantissa, &p).Sign() == 0
+}
+
+// IsNormal returns true if x is finite, nonzero, and not subnormal.
+func (x *Big) IsNormal() bool {
+	return x.form == finite && !x.ez() && !x.IsSubnormal()
 }
 
-// Log sets z to the base-e logarithm of x and returns z.
-/*func (z *Big) Log(x *Big) *Big {
-	if x.ltez() {
-		panic(ErrNaN{"base-e logarithm of x <= 0"})
+// IsSubnormal returns true if x is finite, nonzero, and its adjusted exponent
+// is less than the Emin of x's Context. Values are never subnormal if x's
+// Context has no exponent limits.
+func (x *Big) IsSubnormal() bool {
+	return x.form == finite && !x.ez() && x.ctx.limited() &&
+		x.adjusted() < int64(x.ctx.Emin)
+}
+
+// Log sets z to the natural logarithm of x and returns z. The result is
+// correctly rounded to z's precision using z's RoundingMode. If z's precision
+// is unlimited the result is rounded to DefaultPrec digits instead, since
+// ln(x) is only exact if x is one.
+//
+// Log(±0) is -Inf and Log(+Inf) is +Inf. Since the logarithm of a negative
+// number is NaN under IEEE-754 rules, Log sets z to NaN if x < 0.
+func (z *Big) Log(x *Big) *Big {
+	if z.logSpecial(x) {
+		return z
 	}
-	if x.form == inf {
-		z.form = inf
+	if x.Cmp(one) == 0 {
+		// ln(1) == 0
+		return z.setZero(false)
+	}
+	return z.ziv(x, (*Big).log)
+}
+
+// Log10 sets z to the base-10 logarithm of x and returns z. The result is
+// correctly rounded to z's precision using z's RoundingMode. The result is
+// exact if x is an exact power of ten.
+//
+// Special cases are the same as for Log.
+func (z *Big) Log10(x *Big) *Big {
+	if z.logSpecial(x) {
 		return z
 	}
-	mag := int64(x.Prec() - int(x.scale) - 1)
-	if mag < 3 {
-		return z.logNewton(x)
+	if k, ok := isPow10(x); ok {
+		return z.SetMantScale(k, 0).roundToPrec(z.ctx.prec())
 	}
-	root := z.integralRoot(x, mag)
-	lnRoot := root.logNewton(root)
-	return z.Mul(New(mag, 0), lnRoot)
-}*/
+	return z.ziv(x, (*Big).log10)
+}
+
+// Log2 sets z to the base-2 logarithm of x and returns z. The result is
+// correctly rounded to z's precision using z's RoundingMode. The result is
+// exact if x is an exact power of two.
+//
+// Special cases are the same as for Log.
+func (z *Big) Log2(x *Big) *Big {
+	if z.logSpecial(x) {
+		return z
+	}
+	if k, ok := isPow2(x); ok {
+		return z.SetMantScale(k, 0).roundToPrec(z.ctx.prec())
+	}
+	return z.ziv(x, (*Big).log2)
+}
+
+// Logb sets z to the adjusted exponent of x and returns z. That is, the
+// exponent of x when it is written in scientific notation with one digit
+// before the radix. Logb(±Inf) == +Inf and Logb(±0) == -Inf, which raises
+// DivisionByZero.
+func (z *Big) Logb(x *Big) *Big {
+	switch {
+	case z.checkNaNs(x):
+	case x.form == inf:
+		z.SetInf(false)
+	case x.isZero():
+		z.SetInf(true).raise(DivisionByZero)
+	default:
+		z.SetMantScale(x.adjusted(), 0)
+	}
+	return z
+}
+
+// logSpecial handles the special cases for the logarithm functions. It
+// returns true if x was a special case and z has been set to the result.
+func (z *Big) logSpecial(x *Big) bool {
+	switch {
+	case z.checkNaNs(x):
+		return true
+	case x.form == inf:
+		if x.SignBit() {
+			z.setNaN(LogNegative)
+			return true
+		}
+		// log(+Inf) == +Inf
+		z.SetInf(false)
+		return true
+	case x.ez():
+		// log(±0) == -Inf
+		z.SetInf(true)
+		return true
+	case x.ltz():
+		z.setNaN(LogNegative)
+		return true
+	}
+	return false
+}
 
 // MarshalText implements encoding/TextMarshaler.
 func (x *Big) MarshalText() ([]byte, error) {
@@ -519,11 +730,17 @@ func (x *Big) MarshalText() ([]byte, error) {
 
 // Mode returns the rounding mode of x.
 func (x *Big) Mode() RoundingMode {
-	return x.ctx.mode
+	return x.ctx.Mode
 }
 
-// Mul sets z to x * y and returns z.
+// Mul sets z to x * y and returns z. The product is exact unless z's Context
+// has a fixed scale or RoundAll is true.
 func (z *Big) Mul(x, y *Big) *Big {
+	return z.mul(x, y).finish()
+}
+
+// mul sets z to x * y exactly and returns z.
+func (z *Big) mul(x, y *Big) *Big {
 	if x.form == finite && y.form == finite {
 		z.form = finite
 		if x.isCompact() {
@@ -538,31 +755,32 @@ func (z *Big) Mul(x, y *Big) *Big {
 		return z.mulBig(x, y)
 	}
 
-	if x.form == zero && y.form == inf || x.form == inf && y.form == zero {
+	if z.checkNaNs(x, y) {
+		return z
+	}
+
+	if x.isZero() && y.form == inf || x.form == inf && y.isZero() {
 		// ±0 * ±Inf
 		// ±Inf * ±0
-		z.form = zero
-		panic(ErrNaN{"multiplication of zero with infinity"})
+		return z.setNaN(MulZeroInf)
 	}
 
+	neg := x.SignBit() != y.SignBit()
 	if x.form == inf || y.form == inf {
 		// ±Inf * y
 		// x * ±Inf
-		z.form = inf
-		return z
+		return z.SetInf(neg)
 	}
 
 	// ±0 * y
 	// x * ±0
-	z.form = zero
-	return z
+	return z.setZero(neg)
 }
 
 func (z *Big) mulCompact(x, y *Big) *Big {
 	scale, ok := checked.Add32(x.scale, y.scale)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow((x.compact < 0) != (y.compact < 0))
 	}
 
 	prod, ok := checked.Mul(x.compact, y.compact)
@@ -584,8 +802,7 @@ func (z *Big) mulHalf(comp, non *Big) *Big {
 	if comp.scale == non.scale {
 		scale, ok := checked.Add32(comp.scale, non.scale)
 		if !ok {
-			z.form = inf
-			return z
+			return z.overflow((comp.compact < 0) != (non.mantissa.Sign() < 0))
 		}
 		z.mantissa.Mul(big.NewInt(comp.compact), &non.mantissa)
 		z.compact = c.Inflated
@@ -602,8 +819,7 @@ func (z *Big) mulHalf(comp, non *Big) *Big {
 func (z *Big) mulBig(x, y *Big) *Big {
 	scale, ok := checked.Add32(x.scale, y.scale)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.mantissa.Sign() != y.mantissa.Sign())
 	}
 	z.mantissa.Mul(&x.mantissa, &y.mantissa)
 	z.compact = c.Inflated
@@ -614,6 +830,21 @@ func (z *Big) mulBig(x, y *Big) *Big {
 
 // Neg sets z to -x and returns z.
 func (z *Big) Neg(x *Big) *Big {
+	switch {
+	case x.IsNaN():
+		z.compact = x.compact
+		z.form = x.form
+		return z
+	case x.form != finite:
+		// ±0 or ±Inf
+		z.compact = -1
+		if x.SignBit() {
+			z.compact = +1
+		}
+		z.scale = x.scale
+		z.form = x.form
+		return z
+	}
 	if x.isCompact() {
 		z.compact = -x.compact
 	} else {
@@ -625,6 +856,235 @@ func (z *Big) Neg(x *Big) *Big {
 	return z
 }
 
+// NextAfter sets z to the value that is nearest to x in the direction of y
+// and has no more digits than z's precision, and returns z. If x == y, z is
+// set to x. See NextUp and NextDown for more information.
+func (z *Big) NextAfter(x, y *Big) *Big {
+	if z.checkNaNs(x, y) {
+		return z
+	}
+
+	var dir int
+	switch {
+	case x.form == inf && y.form == inf:
+		if x.SignBit() != y.SignBit() {
+			dir = +1
+			if y.SignBit() {
+				dir = -1
+			}
+		}
+	case y.form == inf:
+		dir = +1
+		if y.SignBit() {
+			dir = -1
+		}
+	case x.form == inf:
+		dir = -1
+		if x.SignBit() {
+			dir = +1
+		}
+	default:
+		dir = new(Big).Sub(y, x).Sign()
+	}
+	if dir == 0 {
+		ctx := z.ctx
+		z.Set(x)
+		z.ctx = ctx
+		return z
+	}
+	return z.next(x, dir > 0)
+}
+
+// NextDown sets z to the largest value that is less than x and has no more
+// digits than z's precision, and returns z.
+//
+// Since a Big's scale must fit in an int32, NextDown(±0) is -1e-2147483647
+// and NextDown(+Inf) is the largest finite value with z's precision.
+// NextDown(-Inf) is -Inf. If z's Context has exponent limits, the result is
+// in its range instead: NextDown(±0) is -10 ** Etiny, NextDown(+Inf) is the
+// largest finite value the Context allows, and the next value after the most
+// negative one is -Inf. NextDown panics if z's precision is unlimited.
+func (z *Big) NextDown(x *Big) *Big {
+	return z.next(x, false)
+}
+
+// NextUp sets z to the smallest value that is greater than x and has no more
+// digits than z's precision, and returns z.
+//
+// Since a Big's scale must fit in an int32, NextUp(±0) is 1e-2147483647 and
+// NextUp(-Inf) is the smallest finite value with z's precision. NextUp(+Inf)
+// is +Inf. If z's Context has exponent limits, the result is in its range
+// instead: NextUp(±0) is 10 ** Etiny, NextUp(-Inf) is the smallest finite
+// value the Context allows, and the next value after the largest one is
+// +Inf. NextUp panics if z's precision is unlimited.
+func (z *Big) NextUp(x *Big) *Big {
+	return z.next(x, true)
+}
+
+// next implements NextUp if up is true and NextDown otherwise.
+func (z *Big) next(x *Big, up bool) *Big {
+	zp := z.ctx.prec()
+	if zp == 0 {
+		panic("decimal: NextUp and NextDown require a limited precision")
+	}
+
+	sign := int64(-1)
+	if up {
+		sign = +1
+	}
+
+	// The smallest step is 10 ** Etiny if z's Context has exponent limits.
+	ctx := z.ctx
+	limited := ctx.limited()
+	tiny := int64(MaxScale)
+	if limited && -ctx.etiny() < tiny {
+		tiny = -ctx.etiny()
+	}
+
+	// round rounds z to zp digits and, if z's Context has exponent limits,
+	// brings it into range.
+	round := func() {
+		if limited {
+			z.fix()
+		} else {
+			z.roundToPrec(zp)
+		}
+	}
+
+	switch {
+	case z.checkNaNs(x):
+	case x.form == inf:
+		if x.SignBit() != up {
+			// +Inf (or -Inf) is already as large (or small) as possible.
+			z.SetInf(!up)
+			break
+		}
+		if limited {
+			z.setMax(up)
+			break
+		}
+		// The finite value with the largest magnitude.
+		var m big.Int
+		p := pow.BigTen(int64(zp))
+		m.Sub(&p, oneInt)
+		if up {
+			m.Neg(&m)
+		}
+		z.SetBigMantScale(&m, MinScale)
+	case x.isZero():
+		z.SetMantScale(sign, int32(tiny))
+	default:
+		z.Set(x)
+		z.ctx = ctx
+		z.ctx.Traps = 0
+		z.ctx.Mode = ToNegativeInf
+		if up {
+			z.ctx.Mode = ToPositiveInf
+		}
+		if z.Prec() > int(zp) || limited {
+			// If x isn't representable with zp digits or in z's exponent
+			// range, rounding toward the correct infinity gives the next
+			// value.
+			x0 := new(Big).Set(z)
+			round()
+			if x0.Cmp(z) != 0 {
+				break
+			}
+		}
+		// Otherwise, nudge x by a value smaller than one unit in the last
+		// place, even if x is a power of ten and the next value has a
+		// smaller exponent, and then round toward the correct infinity.
+		s := int64(zp) - z.adjusted() + 1
+		if s <= tiny && limited {
+			s = tiny + 1
+		}
+		if s > MaxScale {
+			// The last place is fixed by MaxScale, so the sum is exact. If
+			// it's zero it keeps x's sign.
+			neg := z.SignBit()
+			if z.add(z, New(sign, MaxScale)).isZero() {
+				z.setZero(neg)
+			}
+			break
+		}
+		z.add(z, New(sign, int32(s)))
+		round()
+	}
+	z.ctx = ctx
+	return z
+}
+
+// Pow sets z to x ** y and returns z. The result is correctly rounded to z's
+// precision using z's RoundingMode.
+//
+// If y is an integer the power is computed exactly and rounded once.
+// Otherwise, it's computed as e ** (y * ln(x)). If z's precision is unlimited
+// the result is rounded to DefaultPrec digits, unless y is a non-negative
+// integer and the exact power is small enough to compute.
+//
+// Pow(x, ±0) and Pow(1, y) are 1 for any x and y, even a quiet NaN.
+// Otherwise, if x or y is NaN the result is NaN. Pow(±0, y) is Inf if y < 0
+// and 0 if y > 0, negative if x is -0 and y is an odd integer. Pow(x, +Inf)
+// is +Inf if |x| > 1 and 0 if |x| < 1, and the reverse holds for
+// Pow(x, -Inf). Pow(-1, ±Inf) is 1. Pow(±Inf, y) follows from treating ±Inf
+// as a very large number. Since a negative number raised to a non-integer
+// power is NaN under IEEE-754 rules, Pow sets z to NaN if x < 0 and y is not
+// an integer.
+func (z *Big) Pow(x, y *Big) *Big {
+	if !x.IsSignaling() && !y.IsSignaling() &&
+		(y.isZero() || (x.form == finite && x.Cmp(one) == 0)) {
+		// x ** 0 == 1
+		// 1 ** y == 1
+		return z.SetMantScale(1, 0)
+	}
+	if z.checkNaNs(x, y) {
+		return z
+	}
+
+	if y.form == inf {
+		// |x| > 1: x ** +Inf == +Inf, x ** -Inf == 0
+		// |x| < 1: x ** +Inf == 0, x ** -Inf == +Inf
+		cmp := 1
+		if x.form != inf {
+			cmp = new(Big).Abs(x).Cmp(one)
+		}
+		switch {
+		case cmp == 0:
+			// ±1 ** ±Inf == 1
+			return z.SetMantScale(1, 0)
+		case (cmp > 0) != y.SignBit():
+			z.SetInf(false)
+		default:
+			z.setZero(false)
+		}
+		return z
+	}
+
+	if x.form == finite && x.ltz() && !y.IsInt() {
+		return z.setNaN(PowNegNonInt)
+	}
+
+	if x.form == inf || x.ez() {
+		// ±0 ** y
+		// ±Inf ** y
+		//
+		// The result is negative if x is negative and y is an odd
+		// integer. E.g., -Inf ** 3 == -Inf and -0 ** -3 == -Inf.
+		neg := x.SignBit() && y.isOdd()
+		if (x.form == inf) == y.gtz() {
+			return z.SetInf(neg)
+		}
+		return z.setZero(neg)
+	}
+
+	if y.IsInt() {
+		if n := y.Int(); n.IsInt64() && z.powInt(x, n.Int64()) {
+			return z
+		}
+	}
+	return z.ziv(x, func(z, x *Big) *Big { return z.pow(x, y) })
+}
+
 // Prec returns the precision of z. That is, it returns the number of
 // decimal digits z requires.
 func (x *Big) Prec() int {
@@ -638,72 +1098,236 @@ func (x *Big) Prec() int {
 	return arith.BigLength(&x.mantissa)
 }
 
-// Quo sets z to x / y and returns z.
+// Quantize sets z to x rescaled to the given scale and returns z. If
+// digits must be removed, the result is rounded using z's RoundingMode. For
+// example, quantizing 1.235 to a scale of 2 gives 1.24 when rounding to the
+// nearest even digit, and quantizing 1.2 to a scale of 3 gives 1.200.
+//
+// Quantize sets z to NaN and raises only InvalidOperation if x is infinite or
+// if the result would have more digits than z's precision allows.
+func (z *Big) Quantize(x *Big, scale int32) *Big {
+	if z.checkNaNs(x) {
+		return z
+	}
+	if x.form == inf {
+		return z.setNaN(QuantizeInf)
+	}
+	if x.isZero() {
+		z.setZero(x.SignBit())
+		z.scale = scale
+		return z
+	}
+
+	// The result has about x.adjusted() + scale + 1 digits. Check it before
+	// rescaling so we don't allocate a huge mantissa just to throw it away.
+	zp := int64(z.ctx.prec())
+	if zp > 0 && x.adjusted()+int64(scale)+1 > zp {
+		return z.setNaN(QuantizeMinMax)
+	}
+
+	ctx := z.ctx
+	z.Set(x)
+	z.ctx = ctx
+
+	if scale >= z.scale {
+		shift, ok := checked.Sub32(scale, z.scale)
+		if !ok {
+			return z.setNaN(QuantizeMinMax)
+		}
+		return z.pad(shift)
+	}
+
+	// Rounding away from zero could add a digit, e.g. 9.99 -> 10.0, and make
+	// the result too long. That only raises InvalidOperation, so hold back
+	// the conditions raised by rounding until the result is known to fit.
+	z.ctx.Conditions, z.ctx.Traps = 0, 0
+	z.shrink(int64(z.scale)-int64(scale), z.ctx.Mode)
+	z.scale = scale
+	c := z.ctx.Conditions
+	z.ctx = ctx
+	if zp > 0 && int64(z.Prec()) > zp {
+		return z.setNaN(QuantizeMinMax)
+	}
+	return z.raise(c)
+}
+
+// Quo sets z to x / y and returns z. It raises Inexact and Rounded if the
+// quotient isn't exact and DivisionByZero if x is finite and nonzero and y is
+// zero.
 func (z *Big) Quo(x, y *Big) *Big {
-	if x.form == finite && y.form == finite {
+	if x.form == finite && y.form == finite && !y.ez() {
 		z.form = finite
 		// x / y (common case)
-		if x.isCompact() {
-			if y.isCompact() {
-				return z.quoCompact(x, y)
-			}
-			return z.quoBig(&Big{
-				compact:  c.Inflated,
-				mantissa: *big.NewInt(x.compact),
-				ctx:      x.ctx,
-				form:     x.form,
-				scale:    x.scale,
-			}, y)
+		switch {
+		case z.ctx.Fixed:
+			return z.quoScale(x, y)
+		case z.ctx.limited():
+			return z.quoFix(x, y)
 		}
-		if y.isCompact() {
-			return z.quoBig(x, &Big{
-				compact:  c.Inflated,
-				mantissa: *big.NewInt(y.compact),
-				ctx:      y.ctx,
-				form:     y.form,
-				scale:    y.scale,
-			})
-		}
-		return z.quoBig(x, y)
+		return z.quo(x, y)
 	}
 
-	if x.form == zero && y.form == zero || x.form == inf && y.form == inf {
+	if z.checkNaNs(x, y) {
+		return z
+	}
+
+	if x.isZero() && y.isZero() {
 		// ±0 / ±0
+		return z.setNaN(QuoZeroZero)
+	}
+
+	if x.form == inf && y.form == inf {
 		// ±Inf / ±Inf
-		z.form = zero
-		panic(ErrNaN{"division of zero by zero or infinity by infinity"})
+		return z.setNaN(QuoInfInf)
 	}
 
-	if x.form == zero || y.form == inf {
+	neg := x.SignBit() != y.SignBit()
+	if x.isZero() || y.form == inf {
 		// ±0 / y
 		// x / ±Inf
-		z.form = zero
-		return z
+		return z.setZero(neg).finish()
 	}
 
-	// x / ±0
+	if x.form == finite {
+		// x / ±0
+		z.raise(DivisionByZero)
+	}
 	// ±Inf / y
-	z.form = inf
-	return z
+	return z.SetInf(neg)
+}
+
+// quo sets z to x / y rounded to z's precision and returns z. x and y must be
+// finite and y must be nonzero.
+func (z *Big) quo(x, y *Big) *Big {
+	if x.isCompact() {
+		if y.isCompact() {
+			return z.quoCompact(x, y)
+		}
+		return z.quoBig(&Big{
+			compact:  c.Inflated,
+			mantissa: *big.NewInt(x.compact),
+			ctx:      x.ctx,
+			form:     x.form,
+			scale:    x.scale,
+		}, y)
+	}
+	if y.isCompact() {
+		return z.quoBig(x, &Big{
+			compact:  c.Inflated,
+			mantissa: *big.NewInt(y.compact),
+			ctx:      y.ctx,
+			form:     y.form,
+			scale:    y.scale,
+		})
+	}
+	return z.quoBig(x, y)
+}
+
+// quoFix sets z to x / y, rounded once to z's precision and exponent limits,
+// and returns z. x and y must be finite and y must be nonzero.
+//
+// The quotient is truncated to two extra digits. If that's inexact, its last
+// digit is bumped if it's 0 or 5, so the digits after the precision are never
+// mistaken for an exact value or a tie when fix rounds the quotient.
+func (z *Big) quoFix(x, y *Big) *Big {
+	neg := x.SignBit() != y.SignBit()
+	ctx := z.ctx
+	z.ctx.Prec = ctx.prec() + 2
+	z.ctx.Mode = ToZero
+	z.ctx.Conditions = 0
+	z.ctx.Traps = 0
+	z.quo(x, y)
+	cond := z.ctx.Conditions
+	z.ctx = ctx
+
+	if cond&Overflow != 0 {
+		// The scale overflowed, so |x / y| is either far too large or far too
+		// small to be represented.
+		if x.adjusted() > y.adjusted() {
+			return z.overflow(neg)
+		}
+		// Let fix round a value below the smallest subnormal.
+		v := int64(1)
+		if neg {
+			v = -1
+		}
+		return z.SetMantScale(v, int32(2-ctx.etiny())).fix()
+	}
+
+	if cond&Inexact != 0 {
+		z.reround(neg)
+	} else {
+		// The quotient is exact, so the extra digits can be zeros that fix
+		// shouldn't report as rounded. Remove them first.
+		z.strip(2)
+	}
+	return z.fix()
+}
+
+// quoScale sets z to x / y, rounded once to z's Context's Scale, and returns
+// z. x and y must be finite and y must be nonzero.
+//
+// Like quoFix, it truncates the quotient to at least two digits past the
+// scale and lets reround mark an inexact quotient before rounding it.
+func (z *Big) quoScale(x, y *Big) *Big {
+	neg := x.SignBit() != y.SignBit()
+	scale := int64(z.ctx.Scale)
+
+	// x / y has at most x.adjusted() - y.adjusted() + 1 integral digits.
+	prec := x.adjusted() - y.adjusted() + 1 + scale + 2
+	if prec < 1 {
+		// |x / y| < 10 ** -(scale + 2), so it's less than half of the last
+		// place. Round a value that's too small to matter.
+		v := int64(1)
+		if neg {
+			v = -1
+		}
+		return z.SetMantScale(v, int32(scale+2)).fixScale()
+	}
+	if prec > MaxPrec {
+		return z.overflow(neg)
+	}
+
+	ctx := z.ctx
+	z.ctx.Prec = int32(prec)
+	z.ctx.Mode = ToZero
+	z.ctx.Conditions = 0
+	z.ctx.Traps = 0
+	z.quo(x, y)
+	cond := z.ctx.Conditions
+	z.ctx = ctx
+
+	if cond&Overflow != 0 {
+		return z.overflow(neg)
+	}
+	if cond&Inexact != 0 {
+		z.reround(neg)
+	} else if int64(z.scale) > scale {
+		z.strip(int64(z.scale) - scale)
+	}
+	return z.fixScale()
 }
 
 func (z *Big) quoAndRound(x, y int64) *Big {
 	// Quotient
 	z.compact = x / y
 
+	// Remainder
+	r := x % y
+	if r != 0 {
+		z.raise(Inexact | Rounded)
+	}
+
 	// ToZero means we can ignore remainder.
-	if z.ctx.mode == ToZero {
+	if z.ctx.Mode == ToZero {
 		return z
 	}
 
-	// Remainder
-	r := x % y
-
 	sign := int64(1)
 	if (x < 0) != (y < 0) {
 		sign = -1
 	}
-	if r != 0 && z.needsInc(y, r, sign > 0, z.compact&1 != 0) {
+	if r != 0 && z.needsInc(y, r, sign > 0, arith.Abs(z.compact%10)) {
 		z.compact += sign
 	}
 	return z.Round(z.ctx.prec())
@@ -712,16 +1336,14 @@ func (z *Big) quoAndRound(x, y int64) *Big {
 func (z *Big) quoCompact(x, y *Big) *Big {
 	if x.compact == 0 {
 		if y.compact == 0 {
-			panic(ErrNaN{"division of zero by zero"})
+			return z.setNaN(QuoZeroZero)
 		}
-		z.form = 0
-		return z
+		return z.setZero(x.SignBit() != y.SignBit())
 	}
 
 	scale, ok := checked.Sub32(x.scale, y.scale)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 
 	zp := z.ctx.prec()
@@ -735,15 +1357,13 @@ func (z *Big) quoCompact(x, y *Big) *Big {
 
 	scale, ok = checked.Int32(int64(scale) + int64(yp) - int64(xp) + int64(zp))
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 	z.scale = scale
 
 	shift, ok := checked.SumSub(zp, yp, xp)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 
 	xs, ys := x.compact, y.compact
@@ -759,8 +1379,7 @@ func (z *Big) quoCompact(x, y *Big) *Big {
 	// shift < 0
 	ns, ok := checked.Sub32(xp, zp)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 
 	// new scale == yp, so no inflation needed.
@@ -769,8 +1388,7 @@ func (z *Big) quoCompact(x, y *Big) *Big {
 	}
 	shift, ok = checked.Sub32(ns, yp)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 	ys, ok = checked.MulPow10(ys, shift)
 	if !ok {
@@ -783,8 +1401,7 @@ func (z *Big) quoCompact(x, y *Big) *Big {
 func (z *Big) quoBig(x, y *Big) *Big {
 	scale, ok := checked.Sub32(x.scale, y.scale)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 
 	zp := z.ctx.prec()
@@ -798,15 +1415,13 @@ func (z *Big) quoBig(x, y *Big) *Big {
 
 	scale, ok = checked.Int32(int64(scale) + int64(yp) - int64(xp) + int64(zp))
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 	z.scale = scale
 
 	shift, ok := checked.SumSub(zp, yp, xp)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 	if shift > 0 {
 		xs := checked.MulBigPow10(new(big.Int).Set(&x.mantissa), shift)
@@ -816,13 +1431,11 @@ func (z *Big) quoBig(x, y *Big) *Big {
 	// shift < 0
 	ns, ok := checked.Sub32(xp, zp)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 	shift, ok = checked.Sub32(ns, yp)
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(x.SignBit() != y.SignBit())
 	}
 	ys := checked.MulBigPow10(new(big.Int).Set(&y.mantissa), shift)
 	return z.quoBigAndRound(&x.mantissa, ys)
@@ -831,9 +1444,12 @@ func (z *Big) quoBig(x, y *Big) *Big {
 func (z *Big) quoBigAndRound(x, y *big.Int) *Big {
 	z.compact = c.Inflated
 
-	q, r := z.mantissa.QuoRem(x, y, new(big.Int))
+	_, r := z.mantissa.QuoRem(x, y, new(big.Int))
+	if r.Sign() != 0 {
+		z.raise(Inexact | Rounded)
+	}
 
-	if z.ctx.mode == ToZero {
+	if z.ctx.Mode == ToZero {
 		return z
 	}
 
@@ -841,9 +1457,7 @@ func (z *Big) quoBigAndRound(x, y *big.Int) *Big {
 	if (x.Sign() < 0) != (y.Sign() < 0) {
 		sign = -1
 	}
-	odd := new(big.Int).And(q, oneInt).Sign() != 0
-
-	if r.Sign() != 0 && z.needsIncBig(y, r, sign > 0, odd) {
+	if r.Sign() != 0 && z.needsIncBig(y, r, sign > 0, z.lastDigit()) {
 		z.mantissa.Add(&z.mantissa, big.NewInt(sign))
 	}
 	return z.Round(z.ctx.prec())
@@ -852,6 +1466,8 @@ func (z *Big) quoBigAndRound(x, y *big.Int) *Big {
 // Round rounds z down to n digits of precision and returns z. The result is
 // undefined if n is less than zero. No rounding will occur if n is zero.
 // The result of Round will always be within the interval [⌊z⌋, z].
+// Round raises Rounded if any digits are removed and Inexact if any of them
+// were nonzero.
 func (z *Big) Round(n int32) *Big {
 	zp := z.Prec()
 	if n <= 0 || int(n) < zp-int(z.scale) || z.form != finite {
@@ -860,13 +1476,13 @@ func (z *Big) Round(n int32) *Big {
 
 	shift, ok := checked.Sub(int64(zp), int64(n))
 	if !ok {
-		z.form = inf
-		return z
+		return z.overflow(z.SignBit())
 	}
 	if shift <= 0 {
 		return z
 	}
 	z.scale -= int32(shift)
+	z.raise(Rounded)
 
 	if z.isCompact() {
 		val, ok := pow.Ten64(shift)
@@ -879,6 +1495,50 @@ func (z *Big) Round(n int32) *Big {
 	return z.quoBigAndRound(&z.mantissa, &val)
 }
 
+// SameQuantum returns true if x and y have the same scale, are both
+// infinite, or are both NaN.
+func (x *Big) SameQuantum(y *Big) bool {
+	if x.IsNaN() || y.IsNaN() {
+		return x.IsNaN() && y.IsNaN()
+	}
+	if x.form == inf || y.form == inf {
+		return x.form == y.form
+	}
+	return x.scale == y.scale
+}
+
+// Scalb sets z to x * 10**n and returns z. The multiplication is done by
+// adjusting the scale of x, so the mantissa is never modified. If the
+// resulting scale would overflow z is set to ±Inf, and if it would underflow
+// z is set to 0. If z's Context has exponent limits, the result is rounded to
+// its precision and brought into its exponent range, so it may overflow,
+// underflow, or become subnormal.
+func (z *Big) Scalb(x *Big, n int32) *Big {
+	if z.checkNaNs(x) {
+		return z
+	}
+	ctx := z.ctx
+	if x.form != finite {
+		z.Set(x)
+		z.ctx = ctx
+		return z
+	}
+	scale, ok := checked.Sub32(x.scale, n)
+	if !ok {
+		if n < 0 {
+			return z.underflow(x.SignBit())
+		}
+		return z.overflow(x.SignBit())
+	}
+	z.Set(x)
+	z.ctx = ctx
+	z.scale = scale
+	if z.ctx.limited() {
+		return z.fix()
+	}
+	return z
+}
+
 // Scale returns x's scale.
 func (x *Big) Scale() int32 {
 	return x.scale
@@ -903,7 +1563,8 @@ func (z *Big) Set(x *Big) *Big {
 // SetBigMantScale sets z to the given value and scale.
 func (z *Big) SetBigMantScale(value *big.Int, scale int32) *Big {
 	if value.Sign() == 0 {
-		z.form = zero
+		z.setZero(false)
+		z.scale = scale
 		return z
 	}
 	z.scale = scale
@@ -932,13 +1593,18 @@ func (z *Big) SetContext(ctx Context) *Big {
 // To do this, it scales up the provided number by its scale. This involves
 // rounding, so approximately 2.3% of decimals created from floats will have a
 // rounding imprecision of ± 1 ULP.
+//
+// SetFloat64 raises Inexact and Rounded if z isn't exactly equal to value.
 func (z *Big) SetFloat64(value float64) *Big {
 	if value == 0 {
-		z.form = 0
-		return z
+		return z.setZero(math.Signbit(value))
+	}
+	if math.IsNaN(value) {
+		return z.SetNaN(false, 0)
 	}
 
 	var scale int32
+	f := value
 
 	// If value is not an integer (has a fractional part) bump its value up
 	// and find the appropriate scale.
@@ -948,12 +1614,8 @@ func (z *Big) SetFloat64(value float64) *Big {
 		value *= math.Pow10(int(scale))
 	}
 
-	if math.IsNaN(value) {
-		panic(ErrNaN{"NewFromFloat(NaN)"})
-	}
 	if math.IsInf(value, 0) {
-		z.form = inf
-		return z
+		return z.SetInf(value < 0)
 	}
 
 	// Given float64(math.MaxInt64) == math.MaxInt64.
@@ -969,19 +1631,27 @@ func (z *Big) SetFloat64(value float64) *Big {
 	}
 	z.scale = scale
 	z.form = finite
+	if fr != 0 && z.CmpFloat64(f) != 0 {
+		z.raise(Inexact | Rounded)
+	}
 	return z
 }
 
-// SetInf sets z to Inf and returns z.
-func (x *Big) SetInf() *Big {
-	x.form = inf
-	return x
+// SetInf sets z to -Inf if signbit is true or +Inf otherwise, and returns z.
+func (z *Big) SetInf(signbit bool) *Big {
+	z.form = inf
+	z.compact = +1
+	if signbit {
+		z.compact = -1
+	}
+	return z
 }
 
 // SetMantScale sets z to the given value and scale.
 func (z *Big) SetMantScale(value int64, scale int32) *Big {
 	if value == 0 {
-		z.form = zero
+		z.setZero(false)
+		z.scale = scale
 		return z
 	}
 	z.scale = scale
@@ -995,7 +1665,7 @@ func (z *Big) SetMantScale(value int64, scale int32) *Big {
 
 // SetMode sets z's RoundingMode to mode and returns z.
 func (z *Big) SetMode(mode RoundingMode) *Big {
-	z.ctx.mode = mode
+	z.ctx.Mode = mode
 	return z
 }
 
@@ -1004,7 +1674,7 @@ func (z *Big) SetMode(mode RoundingMode) *Big {
 // which dictates rounding and digits after the radix for lossy operations.
 // The latter describes the number of digits in the decimal.
 func (z *Big) SetPrec(prec int32) *Big {
-	z.ctx.precision = prec
+	z.ctx.Prec = prec
 	return z
 }
 
@@ -1026,15 +1696,39 @@ func (z *Big) SetScale(scale int32) *Big {
 // 	Inf
 // 	+Inf
 // 	-Inf
+// 	NaN
+// 	sNaN
+// 	NaN123
+// 	-0
 //
-//	No distinction is made between +Inf and -Inf.
+//	The sign of zeros and infinities is kept. NaN values may be
+//	followed by an integer payload; their sign is ignored.
 func (z *Big) SetString(s string) (*Big, bool) {
 	// Inf or +Inf or -Inf
 	if (len(s) == 3 && equalFold(s, "Inf")) ||
 		(len(s) == 4 && (s[0] == '+' || s[0] == '-') &&
 			equalFold(s[1:], "Inf")) {
-		z.form = inf
-		return z, true
+		return z.SetInf(s[0] == '-'), true
+	}
+
+	// NaN or sNaN, with an optional sign and payload.
+	t := s
+	if len(t) > 0 && (t[0] == '+' || t[0] == '-') {
+		t = t[1:]
+	}
+	signal := len(t) > 0 && (t[0] == 's' || t[0] == 'S')
+	if signal {
+		t = t[1:]
+	}
+	if len(t) >= 3 && equalFold(t[:3], "NaN") {
+		var p uint64
+		if len(t) > 3 {
+			var err error
+			if p, err = strconv.ParseUint(t[3:], 10, 64); err != nil {
+				return nil, false
+			}
+		}
+		return z.SetNaN(signal, Payload(p)), true
 	}
 
 	var scale int32
@@ -1071,6 +1765,9 @@ func (z *Big) SetString(s string) (*Big, bool) {
 			}
 			err = nerr.Err
 		}
+		if z.compact == c.Inflated {
+			z.mantissa.SetInt64(z.compact)
+		}
 	}
 	if (err == strconv.ErrRange && len(s) == 19) || len(s) > 19 {
 		_, ok := z.mantissa.SetString(s, 10)
@@ -1081,6 +1778,9 @@ func (z *Big) SetString(s string) (*Big, bool) {
 	}
 	z.scale = scale
 	z.form = finite
+	if z.ez() {
+		z.setZero(len(s) > 0 && s[0] == '-')
+	}
 	return z, true
 }
 
@@ -1090,9 +1790,9 @@ func (z *Big) SetString(s string) (*Big, bool) {
 //	 0 if x is ±0
 //	+1 if x >   0
 //
-// Undefined if
+// Sign returns 0 if x is NaN.
 func (x *Big) Sign() int {
-	if x.form == zero {
+	if x.form == zero || x.IsNaN() {
 		return 0
 	}
 	if x.isCompact() {
@@ -1113,24 +1813,43 @@ func (x *Big) Sign() int {
 	return x.mantissa.Sign()
 }
 
-// SignBit returns true if x is negative.
+// SignBit returns true if x is negative, including -0 and -Inf.
 func (x *Big) SignBit() bool {
-	return (x.isCompact() && x.compact < 0) ||
-		(x.isInflated() && x.mantissa.Sign() < 0)
+	switch x.form {
+	case finite:
+		return (x.isCompact() && x.compact < 0) ||
+			(x.isInflated() && x.mantissa.Sign() < 0)
+	case zero, inf:
+		// The sign of ±0 and ±Inf is the sign of compact.
+		return x.compact < 0
+	}
+	return false
 }
 
 // String returns the scientific string representation of x.
-// For special cases, x == nil returns "<nil>" and x.IsInf() returns "Inf".
+// For special cases, x == nil returns "<nil>", x.IsInf() returns "Inf" or
+// "-Inf", a negative zero returns "-0", and x.IsNaN() returns "NaN" or "sNaN"
+// followed by x's payload, if any.
 func (x *Big) String() string {
 	return x.toString(true, lower)
 }
 
 // PlainString returns the plain string representation of x.
-// For special cases, if x == nil returns "<nil>" and x.IsInf() returns "Inf".
+// For special cases, if x == nil returns "<nil>" and x.IsInf() returns "Inf"
+// or "-Inf". Negative zeros and NaN values are formatted as in String.
 func (x *Big) PlainString() string {
 	return x.toString(false, lower)
 }
 
+// EngString returns the engineering string representation of x. It's like
+// String, except that an exponent is always a multiple of three, so there are
+// one to three digits before the radix, and that zeros after the radix are
+// kept. A zero with an exponent keeps it, so it may be written with zeros
+// after the radix, like "0.00e+3" or "0.0".
+func (x *Big) EngString() string {
+	return x.toEngString(lower)
+}
+
 const (
 	lower = 0 // opts for lowercase sci notation
 	upper = 1 // opts for uppercase sci notation
@@ -1140,9 +1859,28 @@ func (x *Big) toString(sci bool, opts byte) string {
 	if x == nil {
 		return "<nil>"
 	}
-	if x.IsInf() {
+	if x.form == inf {
+		if x.SignBit() {
+			return "-Inf"
+		}
 		return "Inf"
 	}
+	if x.IsNaN() {
+		str := "NaN"
+		if x.form == snan {
+			str = "sNaN"
+		}
+		if p := x.Payload(); p != 0 {
+			str += strconv.FormatUint(uint64(p), 10)
+		}
+		return str
+	}
+	if x.form == zero {
+		if x.SignBit() {
+			return "-0"
+		}
+		return "0"
+	}
 
 	// Fast path: return our value as-is.
 	if x.scale == 0 {
@@ -1220,6 +1958,72 @@ func (x *Big) toSciString(str string, b writer, opts byte) string {
 	return b.String()
 }
 
+// toEngString returns the engineering string version of x.
+func (x *Big) toEngString(opts byte) string {
+	if x == nil || x.form != finite && x.form != zero {
+		return x.toString(true, opts)
+	}
+
+	// See http://speleotrove.com/decimal/daconvs.html#reftoeng
+
+	str, left := x.digits()
+	if str == "" {
+		str, left = "0", 1-int64(x.scale)
+	}
+	// Zeros after the radix are significant, so b isn't a buffer.
+	var b bytes.Buffer
+	if x.SignBit() {
+		b.WriteByte('-')
+	}
+
+	// dot is the number of digits before the radix.
+	var dot int64
+	switch {
+	case x.scale >= 0 && left >= -5:
+		// Same as toSciString.
+		dot = left
+	case str == "0":
+		// The exponent is raised to a multiple of three and the
+		// difference becomes zeros after the radix.
+		dot = mod3(left+1) - 1
+	default:
+		// One to three digits before the radix.
+		dot = mod3(left-1) + 1
+	}
+
+	switch n := int64(len(str)); {
+	case dot <= 0:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat("0", int(-dot)))
+		b.WriteString(str)
+	case dot >= n:
+		b.WriteString(str)
+		b.WriteString(strings.Repeat("0", int(dot-n)))
+	default:
+		b.WriteString(str[:dot])
+		b.WriteByte('.')
+		b.WriteString(str[dot:])
+	}
+
+	if e := left - dot; e != 0 {
+		b.WriteByte([2]byte{'e', 'E'}[opts])
+		if e > 0 {
+			b.WriteByte('+')
+		}
+		b.WriteString(strconv.FormatInt(e, 10))
+	}
+	return b.String()
+}
+
+// mod3 returns x modulo 3 in [0, 3).
+func mod3(x int64) int64 {
+	m := x % 3
+	if m < 0 {
+		m += 3
+	}
+	return m
+}
+
 // toPlainString returns the plain string version of x.
 func (x *Big) toPlainString(str string, b writer) string {
 	// Just mantissa + z.scale "0"s -- no radix.
@@ -1228,7 +2032,7 @@ func (x *Big) toPlainString(str string, b writer) string {
 		b.Write(bytes.Repeat([]byte{'0'}, -int(x.scale)))
 		return b.String()
 	}
-	return x.normString(str, new(buffer))
+	return x.normString(str, b)
 }
 
 // normString returns the plain string version of x.
@@ -1261,27 +2065,26 @@ func (x *Big) normString(str string, b writer) string {
 
 // Sqrt sets z to the square root of x and returns z.
 // The precision of Sqrt is determined by z's Context.
-// Sqrt will panic on negative values since Big cannot
-// represent imaginary numbers.
+// Sqrt sets z to NaN on negative values since Big cannot
+// represent imaginary numbers. It raises Inexact and Rounded
+// if the square root isn't exact.
 func (z *Big) Sqrt(x *Big) *Big {
-	if x.SignBit() {
-		panic("math.Sqrt: cannot take square root of negative number")
-	}
-
 	switch {
-	case x.form == inf:
-		z.form = inf
-		return z
-	case x.Sign() == 0:
-		z.form = zero
+	case z.checkNaNs(x):
 		return z
+	case x.isZero():
+		// sqrt(±0) == ±0
+		return z.setZero(x.SignBit())
+	case x.SignBit():
+		return z.setNaN(SqrtNegative)
+	case x.form == inf:
+		return z.SetInf(false)
 	}
 
 	// First fast path---check if x is a perfect square. If it is, we can avoid
 	// having to inflate x and can possibly use can use the hardware SQRT.
 	// Note that we can only catch perfect squares that aren't big.Ints.
 	if sq, ok := perfectSquare(x); ok {
-		z.ctx = x.ctx
 		return z.SetMantScale(sq, 0)
 	}
 
@@ -1300,8 +2103,7 @@ func (z *Big) Sqrt(x *Big) *Big {
 		tmp = new(Big).Set(x)
 	}
 	if !shiftRadixRight(tmp, zpadj) {
-		z.form = inf
-		return z
+		return z.overflow(false)
 	}
 
 	// Second fast path. Check to see if we can calculate the square root without
@@ -1315,6 +2117,19 @@ func (z *Big) Sqrt(x *Big) *Big {
 			ix += n / ix
 			ix >>= 1
 			if ix == p {
+				// ix is truncated, so the root rounds up if n - ix*ix > ix:
+				// that is, if √n > ix + 1/2. It's never a tie, so n - ix*ix
+				// == ix is below the half.
+				if r := n - ix*ix; r != 0 {
+					z.raise(Inexact | Rounded)
+					m := -1
+					if r > ix {
+						m = 1
+					}
+					if z.ctx.Mode.needsInc(m, true, ix%10) {
+						ix++
+					}
+				}
 				return z.SetMantScale(ix, zp)
 			}
 		}
@@ -1330,6 +2145,18 @@ func (z *Big) Sqrt(x *Big) *Big {
 		p.Set(ix)
 		ix.Add(ix, a.Quo(n, ix)).Rsh(ix, 1)
 		if ix.Cmp(&p) == 0 {
+			a.Mul(ix, ix)
+			if a.Sub(n, &a).Sign() != 0 {
+				z.raise(Inexact | Rounded)
+				d := new(big.Int).Rem(ix, c.TenInt).Int64()
+				m := -1
+				if a.Cmp(ix) > 0 {
+					m = 1
+				}
+				if z.ctx.Mode.needsInc(m, true, d) {
+					ix.Add(ix, oneInt)
+				}
+			}
 			return z.SetBigMantScale(ix, zp)
 		}
 	}
@@ -1355,31 +2182,46 @@ func perfectSquare(x *Big) (square int64, ok bool) {
 	return 0, false
 }
 
-// Sub sets z to x - y and returns z.
+// Sub sets z to x - y and returns z. The difference is exact unless z's
+// Context has a fixed scale or RoundAll is true.
 func (z *Big) Sub(x, y *Big) *Big {
+	return z.sub(x, y).finish()
+}
+
+// sub sets z to x - y exactly and returns z.
+func (z *Big) sub(x, y *Big) *Big {
 	if x.form == finite && y.form == finite {
 		// TODO: Write this without using Neg to save an allocation.
-		return z.Add(x, new(Big).Neg(y))
+		return z.add(x, new(Big).Neg(y))
+	}
+
+	if z.checkNaNs(x, y) {
+		return z
 	}
 
 	if x.form == inf && y.form == inf &&
 		x.Sign() == y.Sign() {
 		// +Inf - +Inf
 		// -Inf - -Inf
-		z.form = zero
-		panic(ErrNaN{"subtraction of infinities with equal signs"})
+		return z.setNaN(SubInfInf)
 	}
 
 	if x.form == zero && y.form == zero {
 		// ±0 - ±0
-		z.form = zero
-		return z
+		neg := x.SignBit()
+		if neg == y.SignBit() {
+			neg = z.ctx.Mode == ToNegativeInf
+		}
+		return z.setZero(neg)
 	}
 
 	if x.form == inf || y.form == zero {
 		// ±Inf - y
 		// x - ±0
-		return z.Set(x)
+		ctx := z.ctx
+		z.Set(x)
+		z.ctx = ctx
+		return z
 	}
 
 	// ±0 - y
@@ -1387,6 +2229,50 @@ func (z *Big) Sub(x, y *Big) *Big {
 	return z.Neg(y)
 }
 
+// Ulp sets z to the unit in the last place of x when x has z's precision and
+// returns z. In other words, z is set to the magnitude of the difference
+// between two adjacent values with the same exponent as x. If z's precision
+// is unlimited, z is set to the unit in the last place of x as it is
+// currently stored.
+//
+// If z's Context has exponent limits, z is at least 10 ** Etiny, the
+// smallest subnormal value, and it overflows if it's too large.
+//
+// Ulp(±Inf) is +Inf and, since a Big's scale must fit in an int32, Ulp(±0)
+// is 1e-2147483647, or 10 ** Etiny if z's Context has exponent limits.
+func (z *Big) Ulp(x *Big) *Big {
+	var s int64
+	switch zp := z.ctx.prec(); {
+	case z.checkNaNs(x):
+		return z
+	case x.form == inf:
+		return z.SetInf(false)
+	case x.isZero():
+		s = MaxScale
+	case zp != 0:
+		s = int64(zp) - x.adjusted() - 1
+	default:
+		s = int64(x.scale)
+	}
+
+	limited := z.ctx.limited()
+	if limited && s > -z.ctx.etiny() {
+		s = -z.ctx.etiny()
+	}
+	switch {
+	case s > MaxScale:
+		s = MaxScale
+	case s < MinScale:
+		// 1e+2147483648 and larger overflow.
+		return z.SetInf(false)
+	}
+	z.SetMantScale(1, int32(s))
+	if limited {
+		return z.fix()
+	}
+	return z
+}
+
 // UnmarshalText implements encoding/TextUnmarshaler.
 func (x *Big) UnmarshalText(data []byte) error {
 	_, ok := x.SetString(string(data))
@@ -0,0 +1,113 @@
+package decimal
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EricLagergren/decimal/suite"
+)
+
+func TestSuite_Clamping(t *testing.T)  { testSuite(t, "Clamping") }
+func TestSuite_Overflow(t *testing.T)  { testSuite(t, "Overflow") }
+func TestSuite_Rounding(t *testing.T)  { testSuite(t, "Rounding") }
+func TestSuite_Underflow(t *testing.T) { testSuite(t, "Underflow") }
+
+var suiteModes = map[big.RoundingMode]RoundingMode{
+	big.ToNearestEven: ToNearestEven,
+	big.ToNearestAway: ToNearestAway,
+	big.ToZero:        ToZero,
+	big.ToNegativeInf: ToNegativeInf,
+	big.ToPositiveInf: ToPositiveInf,
+}
+
+// suiteExceptions maps the conditions that have an IEEE 754-2008 equivalent
+// to the suite's exceptions.
+var suiteExceptions = [...]struct {
+	c Condition
+	e suite.Exception
+}{
+	{Inexact, suite.Inexact},
+	{Underflow, suite.Underflow},
+	{Overflow, suite.Overflow},
+	{DivisionByZero, suite.DivByZero},
+	{InvalidOperation, suite.Invalid},
+}
+
+// testSuite runs the decimal cases in suite/tests/name.fptest that don't
+// raise a trapped exception. Every operation rounds its result, so the
+// Contexts set RoundAll.
+func testSuite(t *testing.T, name string) {
+	f, err := os.Open(filepath.Join("suite", "tests", name+".fptest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cases, err := suite.ParseCases(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	for i, c := range cases {
+		if c.Prefix != "d" || c.Excep&c.Trap != 0 || len(c.Inputs) != 2 {
+			continue
+		}
+		var ctx Context
+		switch c.Prec {
+		case 64:
+			ctx = Context64
+		case 128:
+			ctx = Context128
+		default:
+			continue
+		}
+		ctx.Mode = suiteModes[c.Mode]
+		ctx.RoundAll = true
+
+		x, y := newbig(t, string(c.Inputs[0])), newbig(t, string(c.Inputs[1]))
+		z := new(Big).SetContext(ctx)
+		switch c.Op {
+		case suite.Add:
+			z.Add(x, y)
+		case suite.Sub:
+			z.Sub(x, y)
+		case suite.Mul:
+			z.Mul(x, y)
+		case suite.Div:
+			z.Quo(x, y)
+		default:
+			continue
+		}
+		n++
+
+		want := newbig(t, string(c.Output))
+		ok := z.IsInf() == want.IsInf() && z.SignBit() == want.SignBit() &&
+			z.Cmp(want) == 0
+		// Quo doesn't produce the ideal exponent of an exact quotient, so
+		// only its value is checked.
+		if c.Op != suite.Div && z.IsFinite() && !z.isZero() {
+			ok = ok && z.Scale() == want.Scale()
+		}
+		if !ok {
+			t.Errorf("#%d: %s %v %s %s: wanted %s, got %s",
+				i, c.Inputs[0], c.Op, c.Inputs[1], c.Mode, want, z)
+			continue
+		}
+
+		var e suite.Exception
+		for _, v := range suiteExceptions {
+			if z.Conditions()&v.c != 0 {
+				e |= v.e
+			}
+		}
+		if e != c.Excep {
+			t.Errorf("#%d: %s %v %s %s: wanted exceptions %#b, got %#b (%s)",
+				i, c.Inputs[0], c.Op, c.Inputs[1], c.Mode, c.Excep, e, z.Conditions())
+		}
+	}
+	if n == 0 {
+		t.Fatalf("no cases were run")
+	}
+}
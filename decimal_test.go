@@ -1,6 +1,8 @@
 package decimal
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
 	"strconv"
 	"strings"
@@ -101,6 +103,40 @@ func TestBig_BitLen(t *testing.T) {
 	}
 }
 
+func TestBig_Class(t *testing.T) {
+	for i, test := range [...]struct {
+		x     string
+		ctx   Context
+		class string
+	}{
+		0:  {"1e-383", Context64, "+Normal"},
+		1:  {"9e-384", Context64, "+Subnormal"},
+		2:  {"-1e-398", Context64, "-Subnormal"},
+		3:  {"-1e-398", Context{}, "-Normal"},
+		4:  {"1234567", Context32, "+Normal"},
+		5:  {"0", Context64, "+Zero"},
+		6:  {"-0", Context64, "-Zero"},
+		7:  {"Inf", Context64, "+Infinity"},
+		8:  {"-Inf", Context64, "-Infinity"},
+		9:  {"NaN", Context64, "NaN"},
+		10: {"sNaN", Context64, "sNaN"},
+	} {
+		x, ok := new(Big).SetContext(test.ctx).SetString(test.x)
+		if !ok {
+			t.Fatalf("#%d: bad input %q", i, test.x)
+		}
+		if class := x.Class(); class != test.class {
+			t.Errorf("#%d: Class(%s) wanted %s, got %s", i, test.x, test.class, class)
+		}
+		if n := x.IsNormal(); n != strings.HasSuffix(test.class, "Normal") {
+			t.Errorf("#%d: IsNormal(%s) wanted %t, got %t", i, test.x, !n, n)
+		}
+		if n := x.IsSubnormal(); n != strings.HasSuffix(test.class, "Subnormal") {
+			t.Errorf("#%d: IsSubnormal(%s) wanted %t, got %t", i, test.x, !n, n)
+		}
+	}
+}
+
 func TestBig_Cmp(t *testing.T) {
 	const (
 		lesser  = -1
@@ -136,81 +172,725 @@ func TestBig_Cmp(t *testing.T) {
 		// Differing signs
 		{new(Big).Set(large).Neg(large), large, lesser},
 		{new(Big).Quo(new(Big).Set(large), New(314156, 5)), large, lesser},
+		// Negatives with different scales
+		{New(-1, 0), New(-25, 1), greater},
+		{New(-25, 1), New(-1, 0), lesser},
+		{New(-10, 1), New(-1, 0), equal},
+		// Large mantissas
+		{newbig(t, "123456789012345678901234567890"), newbig(t, "123456789012345678901234567891"), lesser},
+		{newbig(t, "18446744073709551617"), newbig(t, "36893488147419103233"), lesser},
+		{newbig(t, "1234567890123456789012345678.90"), newbig(t, "1234567890123456789012345678.9"), equal},
+		{newbig(t, "1234567890123456789012345678.91"), newbig(t, "1234567890123456789012345678.9"), greater},
+		{newbig(t, "-1234567890123456789012345678.91"), newbig(t, "-1234567890123456789012345678.9"), lesser},
+		{newbig(t, "9.9"), newbig(t, "12345678901234567890e-19"), greater},
+		// Infinities
+		{new(Big).SetInf(false), large, greater},
+		{large, new(Big).SetInf(false), lesser},
+		{new(Big).SetInf(false), new(Big).SetInf(false), equal},
 	} {
 		r := test.a.Cmp(test.b)
 		if test.v != r {
 			t.Errorf("#%d: wanted %d, got %d", i, test.v, r)
 		}
+		if eq := test.a.Equal(test.b); eq != (test.v == equal) {
+			t.Errorf("#%d: Equal wanted %t, got %t", i, test.v == equal, eq)
+		}
+	}
+
+	x, y := newbig(t, "-1234567890123456789012345.6789"), newbig(t, "-1234567890123456789012345.67891")
+	if n := testing.AllocsPerRun(10, func() { x.Cmp(y) }); n != 0 {
+		t.Errorf("Cmp: wanted 0 allocations, got %g", n)
 	}
 }
 
-/*func TestBig_Exp(t *testing.T) {
+func TestBig_CmpAbs(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y string
+		v    int
+	}{
+		0: {"-2", "1", +1},
+		1: {"-1", "2", -1},
+		2: {"-1.50", "1.5", 0},
+		3: {"0", "-0.001", -1},
+		4: {"Inf", "-12345678901234567890", +1},
+		5: {"-12345678901234567890.5", "12345678901234567890.49", +1},
+	} {
+		if r := newbig(t, test.x).CmpAbs(newbig(t, test.y)); r != test.v {
+			t.Errorf("#%d: CmpAbs(%s, %s) wanted %d, got %d", i, test.x, test.y, test.v, r)
+		}
+	}
+}
+
+func TestBig_CmpFloat64(t *testing.T) {
+	for i, test := range [...]struct {
+		x string
+		f float64
+		v int
+	}{
+		0: {"0.1", 0.1, -1},
+		1: {"0.1000000000000000055511151231257827021181583404541015625", 0.1, 0},
+		2: {"2.5", 2.5, 0},
+		3: {"-3", -3, 0},
+		4: {"1e+30", 1e30, -1},
+		5: {"1000000000000000019884624838656", 1e30, 0},
+		6: {"12345678901234567890", math.Inf(+1), -1},
+		7: {"Inf", math.MaxFloat64, +1},
+		8: {"0", math.SmallestNonzeroFloat64, -1},
+		9: {"-0.5", -0.25, -1},
+	} {
+		if r := newbig(t, test.x).CmpFloat64(test.f); r != test.v {
+			t.Errorf("#%d: CmpFloat64(%s, %g) wanted %d, got %d", i, test.x, test.f, test.v, r)
+		}
+	}
+	if !didPanic(func() { new(Big).CmpFloat64(math.NaN()) }) {
+		t.Error("CmpFloat64(NaN) wanted a panic")
+	}
+}
+
+func TestBig_CmpInt64(t *testing.T) {
+	for i, test := range [...]struct {
+		x string
+		n int64
+		v int
+	}{
+		0: {"5", 5, 0},
+		1: {"5.00", 5, 0},
+		2: {"4.99", 5, -1},
+		3: {"-9223372036854775808", math.MinInt64, 0},
+		4: {"9223372036854775807", math.MaxInt64, 0},
+		5: {"9223372036854775808", math.MaxInt64, +1},
+		6: {"Inf", math.MaxInt64, +1},
+	} {
+		if r := newbig(t, test.x).CmpInt64(test.n); r != test.v {
+			t.Errorf("#%d: CmpInt64(%s, %d) wanted %d, got %d", i, test.x, test.n, test.v, r)
+		}
+	}
+}
+
+func TestBig_CmpTotal(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y string
+		v    int
+	}{
+		0: {"1.0", "1.00", +1},
+		1: {"1.00", "1", -1},
+		2: {"-1.0", "-1.00", -1},
+		3: {"-1", "-1.0", -1},
+		4: {"1.0", "1.0", 0},
+		5: {"1e+3", "1000", +1},
+		6: {"2", "1.00", +1},
+	} {
+		if r := newbig(t, test.x).CmpTotal(newbig(t, test.y)); r != test.v {
+			t.Errorf("#%d: CmpTotal(%s, %s) wanted %d, got %d", i, test.x, test.y, test.v, r)
+		}
+	}
+}
+
+func TestBig_Conditions(t *testing.T) {
+	for i, test := range [...]struct {
+		op   string
+		x, y string
+		c    Condition
+	}{
+		0:  {"Quo", "1", "3", Inexact | Rounded},
+		1:  {"Quo", "1", "4", 0},
+		2:  {"Quo", "1", "-0", DivisionByZero},
+		3:  {"Quo", "Inf", "0", 0},
+		4:  {"Quo", "0", "0", InvalidOperation},
+		5:  {"Sqrt", "2", "", Inexact | Rounded},
+		6:  {"Sqrt", "4", "", 0},
+		7:  {"Sqrt", "-1", "", InvalidOperation},
+		8:  {"Round", "1.2345", "", Inexact | Rounded},
+		9:  {"Round", "1.2000", "", Rounded},
+		10: {"Round", "1.2", "", 0},
+		11: {"Add", "sNaN", "1", InvalidOperation},
+		12: {"Add", "NaN", "1", 0},
+		13: {"Mul", "1e+2000000000", "1e+2000000000", Overflow | Inexact | Rounded},
+		14: {"SetFloat64", "0.1", "", Inexact | Rounded},
+		15: {"SetFloat64", "0.5", "", 0},
+	} {
+		x := newbig(t, test.x)
+		z := new(Big)
+		switch test.op {
+		case "Quo":
+			z.Quo(x, newbig(t, test.y))
+		case "Sqrt":
+			z.Sqrt(x)
+		case "Round":
+			z.Set(x).Round(3)
+		case "Add":
+			z.Add(x, newbig(t, test.y))
+		case "Mul":
+			z.Mul(x, newbig(t, test.y))
+		case "SetFloat64":
+			f, _ := strconv.ParseFloat(test.x, 64)
+			z.SetFloat64(f)
+		}
+		if c := z.Conditions(); c != test.c {
+			t.Errorf("#%d: %s(%s, %s) wanted %s, got %s", i, test.op, test.x, test.y, test.c, c)
+		}
+		if c := z.SetConditions(0).Context().Conditions; c != 0 {
+			t.Errorf("#%d: SetConditions(0) left %s", i, c)
+		}
+	}
+	if s := (Inexact | DivisionByZero).String(); s != "Inexact, DivisionByZero" {
+		t.Errorf("wanted %q, got %q", "Inexact, DivisionByZero", s)
+	}
+}
+
+func TestBig_Context(t *testing.T) {
+	// The operands' Contexts must not affect the results.
+	other := Context{Prec: 2, Mode: ToPositiveInf}
+	for i, test := range [...]struct {
+		ctx  Context
+		op   string
+		x, y string
+		r    string
+		c    Condition
+	}{
+		0:  {Context64, "Quo", "1", "3", "0.3333333333333333", Inexact | Rounded},
+		1:  {Context32, "Quo", "2", "3", "0.6666667", Inexact | Rounded},
+		2:  {Context{Prec: 3, Mode: ToZero}, "Quo", "2", "3", "0.666", Inexact | Rounded},
+		3:  {Context{Prec: 3}, "Add", "1.234", "5", "6.234", 0},
+		4:  {Context{Prec: 3}, "Mul", "1.25", "-2", "-2.5", 0},
+		5:  {Context{Prec: 3}, "Sub", "1", "Inf", "-Inf", 0},
+		6:  {Context{Prec: 3, Mode: ToZero}, "Round", "1.23999", "", "1.23", Inexact | Rounded},
+		7:  {Context{Prec: 3}, "Round", "1.2345", "", "1.23", Inexact | Rounded},
+		8:  {Context32, "Round", "1e+100", "", "Inf", Overflow | Inexact | Rounded},
+		9:  {Context32, "Round", "-1e-110", "", "-0", Underflow | Inexact | Rounded | Subnormal | Clamped},
+		10: {Context{Prec: 5}, "Sqrt", "2", "", "1.41421", Inexact | Rounded},
+		11: {Context{Prec: 5}, "Sqrt", "4", "", "2", 0},
+		12: {Context{Prec: 5}, "Abs", "-1.5", "", "1.5", 0},
+		13: {Context{Prec: 5, Mode: ToZero}, "Quantize", "1.2399", "", "1.23", Inexact | Rounded},
+		14: {Context{Prec: 5}, "Quo", "1", "0", "Inf", DivisionByZero},
+		15: {Context{Prec: 5}, "Round", "sNaN", "", "NaN", InvalidOperation},
+		16: {Context{Prec: 2, Mode: ToNearestZero}, "Quo", "1", "8", "0.12", Inexact | Rounded},
+		17: {Context{Prec: 2, Mode: ToNearestZero}, "Quo", "1", "6", "0.17", Inexact | Rounded},
+		18: {Context{Prec: 2, Mode: AwayFromZero05}, "Quo", "11", "7", "1.6", Inexact | Rounded},
+		19: {Context{Prec: 2, Mode: AwayFromZero05}, "Quo", "2", "7", "0.28", Inexact | Rounded},
+		20: {Context{Prec: 5, Mode: ToNearestZero}, "Sqrt", "2", "", "1.41421", Inexact | Rounded},
+		21: {Context{Prec: 5, Mode: AwayFromZero}, "Sqrt", "2", "", "1.41422", Inexact | Rounded},
+		22: {Context{Prec: 5, Mode: AwayFromZero05}, "Sqrt", "3", "", "1.73206", Inexact | Rounded},
+		23: {Context{Prec: 5, Mode: Unneeded}, "Sqrt", "2.25", "", "1.5", 0},
+		24: {Context{Prec: 3, RoundAll: true}, "Add", "1.235", "5", "6.24", Inexact | Rounded},
+		25: {Context{Prec: 3, RoundAll: true}, "Add", "1.23456", "0", "1.23", Inexact | Rounded},
+		26: {Context{Prec: 3, RoundAll: true}, "Sub", "1000", "1", "999", 0},
+		27: {Context{Prec: 3, RoundAll: true, Mode: ToZero}, "Mul", "1.25", "-2.01", "-2.51", Inexact | Rounded},
+		28: {Context{Prec: 3, RoundAll: true, Emax: 5, Emin: -5}, "Mul", "1000", "1000", "Inf", Overflow | Inexact | Rounded},
+	} {
+		x := newbig(t, test.x).SetContext(other)
+		y := new(Big).SetContext(other)
+		if test.y != "" {
+			y = newbig(t, test.y).SetContext(other)
+		}
+		z := new(Big).SetContext(other)
+		switch test.op {
+		case "Abs":
+			test.ctx.Abs(z, x)
+		case "Add":
+			test.ctx.Add(z, x, y)
+		case "Mul":
+			test.ctx.Mul(z, x, y)
+		case "Quantize":
+			test.ctx.Quantize(z, x, 2)
+		case "Quo":
+			test.ctx.Quo(z, x, y)
+		case "Round":
+			test.ctx.Round(z, x)
+		case "Sqrt":
+			test.ctx.Sqrt(z, x)
+		case "Sub":
+			test.ctx.Sub(z, x, y)
+		}
+		if s := z.String(); s != test.r {
+			t.Errorf("#%d: %s(%s, %s) wanted %s, got %s", i, test.op, test.x, test.y, test.r, s)
+		}
+		if c := z.Conditions(); c != test.c {
+			t.Errorf("#%d: %s(%s, %s) wanted %s, got %s", i, test.op, test.x, test.y, test.c, c)
+		}
+		if c := test.ctx.Conditions; c != test.c {
+			t.Errorf("#%d: %s(%s, %s) wanted %s recorded in the Context, got %s",
+				i, test.op, test.x, test.y, test.c, c)
+		}
+		ctx := z.Context()
+		ctx.Conditions = test.ctx.Conditions
+		if ctx != test.ctx {
+			t.Errorf("#%d: %s(%s, %s) wanted Context %+v, got %+v", i, test.op, test.x, test.y, test.ctx, ctx)
+		}
+	}
+}
+
+func TestContext_Conditions(t *testing.T) {
+	ctx := Context{Prec: 5}
+	z := new(Big)
+	ctx.Quo(z, New(1, 0), New(3, 0))
+	ctx.Add(z, z, New(1, 0))
+	if s := z.String(); s != "1.33333" {
+		t.Errorf("1/3 + 1: wanted 1.33333, got %s", s)
+	}
+	if c := z.Conditions(); c != Inexact|Rounded {
+		t.Errorf("1/3 + 1: wanted %s, got %s", Inexact|Rounded, c)
+	}
+	if c := ctx.Conditions; c != Inexact|Rounded {
+		t.Errorf("1/3 + 1: wanted %s recorded in the Context, got %s", Inexact|Rounded, c)
+	}
+
+	ctx.Quo(z, New(1, 0), new(Big))
+	if c := ctx.Conditions; c != Inexact|Rounded|DivisionByZero {
+		t.Errorf("1/0: wanted %s recorded in the Context, got %s", Inexact|Rounded|DivisionByZero, c)
+	}
+
+	// Trapped conditions are recorded as well.
+	ctx.Traps = InvalidOperation
+	func() {
+		defer func() { recover() }()
+		ctx.Quo(z, new(Big), new(Big))
+		t.Error("0/0: didn't panic")
+	}()
+	if c := ctx.Conditions; c&InvalidOperation == 0 {
+		t.Errorf("0/0: wanted %s recorded in the Context, got %s", InvalidOperation, c)
+	}
+}
+
+func TestContext_MarshalText(t *testing.T) {
+	for i, test := range [...]struct {
+		ctx Context
+		s   string
+	}{
+		0: {NewContext(28, ToZero), "precision: 28, rounding: ToZero"},
+		1: {Context{}, "precision: 0, rounding: ToNearestEven"},
+		2: {Context64, "precision: 16, rounding: ToNearestEven, emax: 384, emin: -383, clamp: true"},
+		3: {Context{Prec: -1, Mode: AwayFromZero05, Clamp: true}, "precision: -1, rounding: AwayFromZero05, clamp: true"},
+		4: {NewFixedContext(2, ToNearestAway), "precision: 0, rounding: ToNearestAway, scale: 2"},
+		5: {Context{Prec: 34, Mode: ToZero, RoundAll: true}, "precision: 34, rounding: ToZero, roundall: true"},
+	} {
+		b, err := test.ctx.MarshalText()
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if string(b) != test.s {
+			t.Errorf("#%d: wanted %q, got %q", i, test.s, b)
+		}
+		var ctx Context
+		if err := ctx.UnmarshalText(b); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if ctx != test.ctx {
+			t.Errorf("#%d: wanted %+v, got %+v", i, test.ctx, ctx)
+		}
+	}
+
+	var cfg struct {
+		Ctx  Context
+		Mode RoundingMode
+	}
+	in := `{"Ctx": "rounding: ToNearestAway,precision:34", "Mode": "ToNegativeInf"}`
+	if err := json.Unmarshal([]byte(in), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Ctx != NewContext(34, ToNearestAway) || cfg.Mode != Floor {
+		t.Errorf("wanted %+v and %s, got %+v and %s", NewContext(34, ToNearestAway), Floor, cfg.Ctx, cfg.Mode)
+	}
+
+	for i, s := range [...]string{
+		"",
+		"precision: 34",
+		"precision: 34, rounding: Nearest",
+		"precision: 1e3, rounding: ToZero",
+		"precision: 34, rounding: ToZero, clamp: maybe",
+		"precision: 34, rounding: ToZero, digits: 2",
+		"rounding: ToZero, clamp: true",
+		"precision 34, rounding: ToZero",
+	} {
+		ctx := Context64
+		if err := ctx.UnmarshalText([]byte(s)); err == nil {
+			t.Errorf("#%d: UnmarshalText(%q) wanted an error", i, s)
+		}
+		if ctx != Context64 {
+			t.Errorf("#%d: UnmarshalText(%q) modified the Context", i, s)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	for r := ToNearestEven; r <= AwayFromZero05; r++ {
+		m, err := ParseRoundingMode(r.String())
+		if err != nil || m != r {
+			t.Errorf("ParseRoundingMode(%q) wanted %s, got %s, %v", r, r, m, err)
+		}
+	}
+	for _, s := range [...]string{"", "toZero", "Ceiling", "RoundingMode(9)"} {
+		if _, err := ParseRoundingMode(s); err == nil {
+			t.Errorf("ParseRoundingMode(%q) wanted an error", s)
+		}
+	}
+	if _, err := RoundingMode(200).MarshalText(); err == nil {
+		t.Errorf("MarshalText(RoundingMode(200)) wanted an error")
+	}
+}
+
+func TestBig_CopySign(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y, res string
+	}{
+		0: {"1.5", "-2", "-1.5"},
+		1: {"-1.5", "2", "1.5"},
+		2: {"-1.5", "-2", "-1.5"},
+		3: {"12345678901234567890", "-1", "-12345678901234567890"},
+		4: {"0", "-1", "-0"},
+		5: {"Inf", "1", "Inf"},
+	} {
+		z := new(Big).CopySign(newbig(t, test.x), newbig(t, test.y))
+		if zs := z.String(); zs != test.res {
+			t.Errorf("#%d: CopySign(%s, %s) wanted %s, got %s", i, test.x, test.y, test.res, zs)
+		}
+	}
+	if z := new(Big).CopySign(new(Big).SetInf(false), newbig(t, "-1")); !z.IsInf() || !z.SignBit() {
+		t.Errorf("CopySign(Inf, -1) wanted -Inf, got %s", z)
+	}
+}
+
+func TestBig_EngString(t *testing.T) {
+	for i, test := range [...]struct {
+		x string
+		r string
+	}{
+		0:  {"123E+1", "1.23e+3"},
+		1:  {"123E+3", "123e+3"},
+		2:  {"12.3E-9", "12.3e-9"},
+		3:  {"-123E-12", "-123e-12"},
+		4:  {"7E-7", "700e-9"},
+		5:  {"7E+1", "70"},
+		6:  {"0E+1", "0.00e+3"},
+		7:  {"0E+2", "0.0e+3"},
+		8:  {"0E+3", "0e+3"},
+		9:  {"0E+4", "0.00e+6"},
+		10: {"0E-7", "0.0e-6"},
+		11: {"0E-8", "0.00e-6"},
+		12: {"0E-9", "0e-9"},
+		13: {"-0E-9", "-0e-9"},
+		14: {"1E+4", "10e+3"},
+		15: {"1.5E+5", "150e+3"},
+		16: {"123456789E-20", "1.23456789e-12"},
+		17: {"12345E-5", "0.12345"},
+		18: {"1E-7", "100e-9"},
+		19: {"1.23E+100", "12.3e+99"},
+		20: {"12345678901234567890E+10", "123.45678901234567890e+27"},
+		21: {"-Inf", "-Inf"},
+		22: {"NaN", "NaN"},
+		23: {"0.00", "0.00"},
+		24: {"0E-1", "0.0"},
+		25: {"2.50", "2.50"},
+	} {
+		if r := newbig(t, test.x).EngString(); r != test.r {
+			t.Errorf("#%d: EngString(%s) wanted %s, got %s", i, test.x, test.r, r)
+		}
+	}
+}
+
+func TestBig_Exp(t *testing.T) {
 	tests := []struct {
 		dec  string
 		exp  string
 		prec int32
 	}{
 		0:  {"-8.748656950366438", "0.000158674", 6},
-		1:  {"40.40850241721978", "354151937244564508", 18},
+		1:  {"40.40850241721978", "354151937244564830", 18},
 		2:  {"73.30000879940332", "6.82007805E+31", 9},
-		3:  {"35.89159984662575", "3868332175374135.326826", 22},
-		4:  {"-4.1512363035379", "0.0157449389235511838", 18},
+		3:  {"35.89159984662575", "3868332175374127.669674", 22},
+		4:  {"-4.1512363035379", "0.015744938923551178", 18},
 		5:  {"-68.12323977553022", "2.59688595E-30", 9},
 		6:  {"-60.614962073263406", "4.734307E-27", 7},
 		7:  {"-4.865041952853346", "0.0077115046651", 11},
-		8:  {"19.704966352217582", "361208659.046814473743298", 24},
+		8:  {"19.704966352217582", "361208659.046814484304066", 24},
 		9:  {"-21.85578630459976", "3.222201E-10", 7},
-		10: {"82.87588357365792", "9.8296695672260140384636E+35", 23},
+		10: {"82.87588357365792", "9.8296695672260552859349E+35", 23},
 		11: {"-25.506698605453636", "8.36722685890E-12", 12},
 		12: {"-76.89354159563261", "4.0323590E-34", 8},
 		13: {"-70.2633346084568", "3.055072349E-31", 10},
 		14: {"-21.75372021081381", "3.56844782783E-10", 12},
 		15: {"2.6624827767715686", "14.331827692113042", 17},
-		16: {"-96.83919622158838", "8.7754914822403566323918E-43", 23},
+		16: {"-96.83919622158838", "8.7754914822403637273608E-43", 23},
 		17: {"97.54660128490326", "2.311802E+42", 7},
-		18: {"19.67234900470102", "349617061.9295282556", 19},
-		19: {"-19.988601487526466", "2.0847821167279748110589E-9", 23},
-		20: {"-61.56525338816619", "1.830417572784093673193404E-27", 25},
-		21: {"-29.48332735888171", "1.5687495703867765401E-13", 20},
+		18: {"19.67234900470102", "349617061.9295286853", 19},
+		19: {"-19.988601487526466", "2.0847821167279755855378E-9", 23},
+		20: {"-61.56525338816619", "1.830417572784095454467870E-27", 25},
+		21: {"-29.48332735888171", "1.5687495703867754441E-13", 20},
 		22: {"-84.74682272069396", "1.5664716288673E-37", 14},
 		23: {"-5.141987940031129", "0.00584606", 6},
-		24: {"-59.64186269471252", "1.2527607590076679E-26", 17},
-		25: {"57.01140301919159", "5.750925436484517E+24", 16},
-		26: {"-53.47126566461959", "5.994105485396352375E-24", 19},
+		24: {"-59.64186269471252", "1.2527607590076703E-26", 17},
+		25: {"57.01140301919159", "5.750925436484516E+24", 16},
+		26: {"-53.47126566461959", "5.994105485396332858E-24", 19},
 		27: {"94.39473267778467", "9.888070E+40", 7},
 		28: {"-1.5172773737968157", "0.21930817", 8},
 		29: {"-59.57754736169733", "1.3360E-26", 5},
 		30: {"-57.08958595213939", "1.60808072677E-25", 12},
 		31: {"73.65129808384759", "9.6906E+31", 5},
-		32: {"-51.00479595622606", "7.061526050698371484E-23", 19},
+		32: {"-51.00479595622606", "7.061526050698382419E-23", 19},
 		33: {"-78.34101448930855", "9.48264955E-35", 9},
 		34: {"-94.76401480997879", "6.99054901284194E-42", 15},
-		35: {"-64.30445473402426", "1.182851288281360976177090E-28", 25},
-		36: {"-84.83774023774372", "1.4303343141056497E-37", 17},
+		35: {"-64.30445473402426", "1.182851288281362865627462E-28", 25},
+		36: {"-84.83774023774372", "1.4303343141056445E-37", 17},
 		37: {"-65.41153068461759", "3.90960760510178E-29", 15},
-		38: {"52.32265526524813", "5.289814713107165786531E+22", 22},
+		38: {"52.32265526524813", "5.289814713107164395365E+22", 22},
 		39: {"0.2856256494736158", "1.330594253347893", 16},
-		40: {"-53.73245080200248", "4.61629035852671E-24", 15},
+		40: {"-53.73245080200248", "4.61629035852672E-24", 15},
 		41: {"95.05660578698794", "1.91672303300E+41", 12},
-		42: {"27.37684913226701", "775558407201.332", 15},
-		43: {"-72.62941915220554", "2.867107906457210561E-32", 19},
+		42: {"27.37684913226701", "775558407201.331", 15},
+		43: {"-72.62941915220554", "2.867107906457218551E-32", 19},
 		44: {"-31.77381246319696", "1.58784672711822E-14", 15},
-		45: {"48.19485014316953", "852623843246004296497.0344", 25},
-		46: {"-26.63866583913405", "2.6975805448955996221E-12", 20},
+		45: {"48.19485014316953", "852623843246002612379.0904", 25},
+		46: {"-26.63866583913405", "2.6975805448955967938E-12", 20},
 		47: {"0.8074038069587886", "2.2421", 5},
 		48: {"-35.836180275711826", "2.7324024E-16", 8},
-		49: {"-48.751960790015346", "6.71881134599976028512284E-22", 24},
+		49: {"-48.751960790015346", "6.71881134599976023330482E-22", 24},
 	}
 	for i, v := range tests {
 		a := newbig(t, v.dec)
 		a.SetPrec(v.prec)
 		as := a.Exp(a).toString(true, upper)
-		fmt.Println(as)
 		if as != v.exp {
-			t.Fatalf("#%d: wanted %s, got %s", i, v.exp, as)
+			t.Errorf("#%d: wanted %s, got %s", i, v.exp, as)
+		}
+	}
+
+	for i, v := range [...]struct {
+		dec  string
+		exp  string
+		prec int32
+		mode RoundingMode
+	}{
+		0:  {"1", "2.7182818284590452353602874713526624977572470937", 50, ToNearestEven},
+		1:  {"0.5", "1.648721270700128", 16, ToZero},
+		2:  {"0.5", "1.648721270700129", 16, ToPositiveInf},
+		3:  {"-987.654", "1.167663677e-429", 10, AwayFromZero},
+		4:  {"2.302585092994046", "10", 16, ToNearestEven},
+		5:  {"1e-20", "1.000000000000001", 16, ToPositiveInf},
+		6:  {"-1e-20", "0.9999999999999999", 16, ToZero},
+		7:  {"-1e-20", "1", 16, ToNearestEven},
+		8:  {"4000000000", "4.102110e+1737177927", 7, ToNearestEven},
+		9:  {"-4000000000", "2.437770e-1737177928", 7, ToNearestEven},
+		10: {"0", "1", 16, ToNearestEven},
+		11: {"Inf", "Inf", 16, ToNearestEven},
+		12: {"1e10", "Inf", 16, ToNearestEven},
+		13: {"-1e10", "0", 16, ToNearestEven},
+		// An unlimited precision rounds to DefaultPrec digits.
+		14: {"1", "2.718281828459045", -1, ToNearestEven},
+		15: {"-2", "0.1353352832366127", -1, ToNearestEven},
+		16: {"1e-20", "1", -1, ToNearestEven},
+	} {
+		z := new(Big).SetPrec(v.prec).SetMode(v.mode)
+		if zs := z.Exp(newbig(t, v.dec)).String(); zs != v.exp {
+			t.Errorf("#%d: Exp(%s) wanted %s, got %s", i, v.dec, v.exp, zs)
+		}
+	}
+
+	if !didPanic(func() { new(Big).SetTraps(Overflow).Exp(New(1, -10)) }) {
+		t.Error("Exp(1e10): wanted panic(Overflow)")
+	}
+
+	// The result is brought into the Context's exponent range.
+	for i, v := range [...]struct {
+		ctx  Context
+		mode RoundingMode
+		dec  string
+		exp  string
+		c    Condition
+	}{
+		0: {Context64, ToNearestEven, "1000", "Inf", Overflow | Inexact | Rounded},
+		1: {Context64, ToZero, "1000", "9.999999999999999e+384", Overflow | Inexact | Rounded},
+		2: {Context64, ToNearestEven, "-1000", "0", Underflow | Subnormal | Inexact | Rounded | Clamped},
+		3: {Context32, ToNearestEven, "-225", "1.922e-98", Underflow | Subnormal | Inexact | Rounded},
+		4: {Context32, ToPositiveInf, "-1000", "1e-101", Underflow | Subnormal | Inexact | Rounded},
+		5: {Context64, ToNearestEven, "1e10", "Inf", Overflow | Inexact | Rounded},
+		6: {Context64, ToZero, "1e10", "9.999999999999999e+384", Overflow | Inexact | Rounded},
+		7: {Context32, ToNearestEven, "-1e20", "0", Underflow | Subnormal | Inexact | Rounded | Clamped},
+		8: {Context32, ToNearestEven, "Inf", "Inf", 0},
+		9: {Context32, ToNearestEven, "-Inf", "0", 0},
+	} {
+		z := new(Big).SetContext(v.ctx).SetMode(v.mode)
+		if zs := z.Exp(newbig(t, v.dec)).String(); zs != v.exp || z.Conditions() != v.c {
+			t.Errorf("#%d: Exp(%s) wanted %s (%s), got %s (%s)",
+				i, v.dec, v.exp, v.c, zs, z.Conditions())
+		}
+	}
+}
+
+func TestBig_Fixed(t *testing.T) {
+	for i, test := range [...]struct {
+		op    string
+		x, y  string
+		scale int32
+		mode  RoundingMode
+		r     string
+		s     int32
+		c     Condition
+	}{
+		0:  {"Quo", "1", "3", 2, ToNearestEven, "0.33", 2, Inexact | Rounded},
+		1:  {"Quo", "2", "3", 2, ToNearestEven, "0.67", 2, Inexact | Rounded},
+		2:  {"Quo", "10", "4", 2, ToNearestEven, "2.5", 2, 0},
+		3:  {"Quo", "1", "8", 2, ToNearestEven, "0.12", 2, Inexact | Rounded},
+		4:  {"Quo", "1", "8", 2, ToNearestAway, "0.13", 2, Inexact | Rounded},
+		5:  {"Quo", "-1", "8", 2, ToNegativeInf, "-0.13", 2, Inexact | Rounded},
+		6:  {"Quo", "1", "1000", 2, ToNearestEven, "0", 2, Inexact | Rounded},
+		7:  {"Quo", "1", "1e+6", 2, ToPositiveInf, "0.01", 2, Inexact | Rounded},
+		8:  {"Quo", "-1", "1e+6", 2, ToNearestEven, "-0", 2, Inexact | Rounded},
+		9:  {"Quo", "1e+20", "3", 2, ToNearestEven, "33333333333333333333.33", 2, Inexact | Rounded},
+		10: {"Quo", "0", "5", 2, ToNearestEven, "0", 2, 0},
+		11: {"Quo", "5", "0", 2, ToNearestEven, "Inf", 0, DivisionByZero},
+		12: {"Quo", "2", "3", 0, ToNearestEven, "1", 0, Inexact | Rounded},
+		13: {"Quo", "1", "6", 4, AwayFromZero05, "0.1666", 4, Inexact | Rounded},
+		14: {"Mul", "1.25", "1.25", 2, ToNearestEven, "1.56", 2, Inexact | Rounded},
+		15: {"Mul", "1234", "1", -2, ToNearestEven, "1.2e+3", -2, Inexact | Rounded},
+		16: {"Add", "1.005", "2", 2, ToNearestEven, "3", 2, Inexact | Rounded},
+		17: {"Add", "1", "0", 2, ToNearestEven, "1", 2, 0},
+		18: {"Sub", "10", "0.001", 2, ToNearestEven, "10", 2, Inexact | Rounded},
+		19: {"Sub", "12345678901234567890.125", "0.1", 2, ToZero, "12345678901234567890.02", 2, Inexact | Rounded},
+	} {
+		x, y := newbig(t, test.x), newbig(t, test.y)
+		z := new(Big).SetContext(NewFixedContext(test.scale, test.mode))
+		switch test.op {
+		case "Add":
+			z.Add(x, y)
+		case "Sub":
+			z.Sub(x, y)
+		case "Mul":
+			z.Mul(x, y)
+		case "Quo":
+			z.Quo(x, y)
+		}
+		if zs := z.String(); zs != test.r || z.Scale() != test.s {
+			t.Errorf("#%d: %s(%s, %s) wanted %s with scale %d, got %s with scale %d",
+				i, test.op, test.x, test.y, test.r, test.s, zs, z.Scale())
+		}
+		if c := z.Conditions(); c != test.c {
+			t.Errorf("#%d: %s(%s, %s) wanted %s, got %s", i, test.op, test.x, test.y, test.c, c)
+		}
+	}
+}
+
+func TestBig_FMA(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y, u string
+		res     string
+		prec    int32
+		mode    RoundingMode
+	}{
+		// Rounding x * y to two digits first would give 0.022.
+		0:  {"0.15", "0.15", "0.0000001", "0.023", 2, ToNearestEven},
+		1:  {"1.23", "4.56", "7.89", "13.4988", 16, ToNearestEven},
+		2:  {"1.23", "4.56", "7.89", "13.4", 3, ToZero},
+		3:  {"-1.23", "4.56", "7.89", "2.2", 2, ToNegativeInf},
+		4:  {"12345678901234567890", "98765432109876543210", "1", "1.219326311370217952237463801111264e+39", 34, ToNearestEven},
+		5:  {"12345678901234567890", "98765432109876543210", "-1", "1.219326311370217952237463801111264e+39", 34, ToPositiveInf},
+		6:  {"3", "-2", "6", "0", 16, ToNearestEven},
+		7:  {"1.5", "2", "-3.0", "0", 16, ToNearestEven},
+		8:  {"0.1", "0.1", "-0.01", "0", 16, ToNearestEven},
+		9:  {"9.99", "9.99", "0.0001", "99.81", 4, AwayFromZero},
+		10: {"2", "0.5", "0.5", "2", 1, ToNearestAway},
+		11: {"Inf", "2", "1", "Inf", 16, ToNearestEven},
+		12: {"2", "3", "Inf", "Inf", 16, ToNearestEven},
+	} {
+		z := new(Big).SetPrec(test.prec).SetMode(test.mode)
+		x, y, u := newbig(t, test.x), newbig(t, test.y), newbig(t, test.u)
+		if zs := z.FMA(x, y, u).String(); zs != test.res {
+			t.Errorf("#%d: FMA(%s, %s, %s) wanted %s, got %s",
+				i, test.x, test.y, test.u, test.res, zs)
+		}
+	}
+
+	// The result is brought into the Context's exponent range.
+	for i, test := range [...]struct {
+		x, y, u string
+		res     string
+		c       Condition
+	}{
+		0: {"1e+96", "10", "0", "Inf", Overflow | Inexact | Rounded},
+		1: {"1.23456e-97", "1", "0", "1.2346e-97", Underflow | Subnormal | Inexact | Rounded},
+		2: {"2", "3", "1e-200", "6", Inexact | Rounded},
+		3: {"Inf", "0", "1", "NaN2", InvalidOperation},
+		4: {"Inf", "1", "-Inf", "NaN1", InvalidOperation},
+	} {
+		z := new(Big).SetContext(Context32)
+		x, y, u := newbig(t, test.x), newbig(t, test.y), newbig(t, test.u)
+		if zs := z.FMA(x, y, u).String(); zs != test.res || z.Conditions() != test.c {
+			t.Errorf("#%d: FMA(%s, %s, %s) wanted %s (%s), got %s (%s)",
+				i, test.x, test.y, test.u, test.res, test.c, zs, z.Conditions())
+		}
+	}
+
+	if !didPanic(func() {
+		new(Big).SetTraps(InvalidOperation).FMA(new(Big).SetInf(false), New(0, 0), New(1, 0))
+	}) {
+		t.Error("FMA(Inf, 0, 1): wanted panic(InvalidOperation)")
+	}
+}
+
+func TestBig_Format(t *testing.T) {
+	for i, test := range [...]struct {
+		x    string
+		mode RoundingMode
+		f    string
+		r    string
+	}{
+		0:  {"123.456", ToNearestEven, "%e", "1.234560e+02"},
+		1:  {"123.456", ToNearestEven, "%E", "1.234560E+02"},
+		2:  {"123.456", ToNearestEven, "%.2e", "1.23e+02"},
+		3:  {"123.456", ToNearestEven, "%f", "123.456000"},
+		4:  {"123.456", ToNearestEven, "%.2f", "123.46"},
+		5:  {"123.456", ToZero, "%.2f", "123.45"},
+		6:  {"-123.451", ToPositiveInf, "%.1f", "-123.4"},
+		7:  {"2.5", ToNearestEven, "%.0f", "2"},
+		8:  {"2.5", ToNearestAway, "%.0f", "3"},
+		9:  {"2.5", Unneeded, "%.0f", "2"},
+		10: {"0.125", ToNearestEven, "%.2f", "0.12"},
+		11: {"99.95", ToNearestEven, "%.1f", "100.0"},
+		12: {"-0.001", ToNearestEven, "%.2f", "-0.00"},
+		13: {"1234567", ToNearestEven, "%g", "1.234567e+06"},
+		14: {"123456", ToNearestEven, "%g", "123456"},
+		15: {"0.0001", ToNearestEven, "%g", "0.0001"},
+		16: {"0.00001", ToNearestEven, "%G", "1E-05"},
+		17: {"2.50", ToNearestEven, "%g", "2.5"},
+		18: {"123.456", ToNearestEven, "%.4g", "123.5"},
+		19: {"123.456", ToNearestEven, "%.2g", "1.2e+02"},
+		20: {"1", ToNearestEven, "%#.3g", "1.00"},
+		21: {"1", ToNearestEven, "%#g", "1.00000"},
+		22: {"1", ToNearestEven, "%#.0f", "1."},
+		23: {"12345678901234567890.125", ToNearestEven, "%.2f", "12345678901234567890.12"},
+		24: {"12345678901234567890.125", ToNearestEven, "%.3e", "1.235e+19"},
+		25: {"12345678901234567890.125", ToNearestEven, "%g", "1.2345678901234567890125e+19"},
+		26: {"1e+100", ToNearestEven, "%e", "1.000000e+100"},
+		27: {"0", ToNearestEven, "%e", "0.000000e+00"},
+		28: {"-0", ToNearestEven, "%g", "-0"},
+		29: {"1.5", ToNearestEven, "%8.2f", "    1.50"},
+		30: {"1.5", ToNearestEven, "%-8.2f|", "1.50    |"},
+		31: {"-1.5", ToNearestEven, "%08.2f", "-0001.50"},
+		32: {"1.5", ToNearestEven, "%+.2f", "+1.50"},
+		33: {"1.5", ToNearestEven, "% .2f", " 1.50"},
+		34: {"1.5", ToNearestEven, "%F", "1.500000"},
+		35: {"1.5e+4", ToNearestEven, "%s", "1.5e+4"},
+		36: {"-1.5", ToNearestEven, "%8v", "    -1.5"},
+		37: {"Inf", ToNearestEven, "%08.2f", "     Inf"},
+		38: {"-Inf", ToNearestEven, "%+e", "-Inf"},
+		39: {"Inf", ToNearestEven, "%+g", "+Inf"},
+		40: {"NaN", ToNearestEven, "%+5.1f", "  NaN"},
+		41: {"1.5", ToNearestEven, "%d", "%!d(*decimal.Big=1.5)"},
+		42: {"7E-7", ToNearestEven, "%z", "700e-9"},
+		43: {"-1.5E+5", ToNearestEven, "%Z", "-150E+3"},
+		44: {"0E+1", ToNearestEven, "%+z", "+0.00e+3"},
+	} {
+		x := newbig(t, test.x).SetMode(test.mode)
+		if r := fmt.Sprintf(test.f, x); r != test.r {
+			t.Errorf("#%d: Sprintf(%q, %s) wanted %q, got %q", i, test.f, test.x, test.r, r)
+		}
+		if c := x.Conditions(); c != 0 {
+			t.Errorf("#%d: Sprintf(%q, %s) raised %s", i, test.f, test.x, c)
 		}
 	}
-}*/
+	if r := fmt.Sprintf("%6.2f", (*Big)(nil)); r != " <nil>" {
+		t.Errorf("wanted %q, got %q", " <nil>", r)
+	}
+}
 
 func TestBig_IsBig(t *testing.T) {
 	for i, test := range [...]struct {
@@ -293,6 +973,12 @@ func TestBig_IsInt(t *testing.T) {
 		"0.000000001e+8",
 		"0.000000001e+9 int",
 		"1.2345e200 int",
+		"0.05",
+		"3.0 int",
+		"12.000 int",
+		"1234.0001",
+		"123456789012345678901234567890.00 int",
+		"123456789012345678901234567890.01",
 		"Inf",
 		"+Inf",
 		"-Inf",
@@ -310,6 +996,224 @@ func TestBig_IsInt(t *testing.T) {
 	}
 }
 
+func TestBig_IsNaN(t *testing.T) {
+	for i, test := range [...]struct {
+		s, res string
+		signal bool
+		p      Payload
+	}{
+		0: {"NaN", "NaN", false, 0},
+		1: {"sNaN", "sNaN", true, 0},
+		2: {"NaN123", "NaN123", false, 123},
+		3: {"-nan", "NaN", false, 0},
+		4: {"+SNAN5", "sNaN5", true, 5},
+	} {
+		x := newbig(t, test.s)
+		if !x.IsNaN() || x.IsSignaling() != test.signal || x.Payload() != test.p {
+			t.Errorf("#%d: SetString(%q) wanted NaN (signaling: %t, payload: %d), got %s",
+				i, test.s, test.signal, test.p, x)
+		}
+		if xs := x.String(); xs != test.res {
+			t.Errorf("#%d: String() wanted %s, got %s", i, test.res, xs)
+		}
+	}
+	for _, s := range [...]string{"NaNx", "sNaN-1", "qNaN"} {
+		if _, ok := new(Big).SetString(s); ok {
+			t.Errorf("SetString(%q) wanted false", s)
+		}
+	}
+
+	add := func(z, x, y *Big) *Big { return z.Add(x, y) }
+	sub := func(z, x, y *Big) *Big { return z.Sub(x, y) }
+	mul := func(z, x, y *Big) *Big { return z.Mul(x, y) }
+	quo := func(z, x, y *Big) *Big { return z.Quo(x, y) }
+	sqrt := func(z, x, _ *Big) *Big { return z.Sqrt(x) }
+	for i, test := range [...]struct {
+		op   func(z, x, y *Big) *Big
+		x, y string
+		p    Payload
+	}{
+		0:  {add, "1", "NaN3", 3},
+		1:  {add, "NaN1", "sNaN2", 2},
+		2:  {sub, "Inf", "Inf", SubInfInf},
+		3:  {sub, "sNaN4", "2", 4},
+		4:  {mul, "0", "Inf", MulZeroInf},
+		5:  {mul, "NaN5", "sNaN6", 6},
+		6:  {quo, "0", "0", QuoZeroZero},
+		7:  {quo, "Inf", "Inf", QuoInfInf},
+		8:  {quo, "sNaN1", "NaN2", 1},
+		9:  {quo, "Inf", "NaN9", 9},
+		10: {sqrt, "-4", "0", SqrtNegative},
+		11: {sqrt, "sNaN8", "0", 8},
+	} {
+		z := test.op(new(Big), newbig(t, test.x), newbig(t, test.y))
+		if !z.IsNaN() || z.IsSignaling() || z.Payload() != test.p {
+			t.Errorf("#%d: wanted quiet NaN with payload %d (%s), got %s", i, test.p, test.p, z)
+		}
+	}
+
+	nan := newbig(t, "NaN")
+	if !didPanic(func() { nan.Cmp(New(1, 0)) }) {
+		t.Error("Cmp(NaN, 1) wanted panic")
+	}
+	if nan.Equal(nan) {
+		t.Error("NaN.Equal(NaN) wanted false")
+	}
+	if newbig(t, "sNaN").CmpTotal(nan) >= 0 || nan.CmpTotal(new(Big).SetInf(false)) <= 0 {
+		t.Error("CmpTotal wanted Inf < sNaN < NaN")
+	}
+}
+
+func TestLocale_Format(t *testing.T) {
+	deva := Locale{
+		Decimal:  ".",
+		Group:    ",",
+		Grouping: []int{3, 2},
+		Digits:   [10]rune{'०', '१', '२', '३', '४', '५', '६', '७', '८', '९'},
+	}
+	for i, test := range [...]struct {
+		l    Locale
+		x    string
+		prec int
+		r    string
+	}{
+		0:  {Locale{}, "-1234567.50", -1, "-1234567.5"},
+		1:  {LocaleUS, "1234567.891", 2, "1,234,567.89"},
+		2:  {LocaleUS, "123", -1, "123"},
+		3:  {LocaleUS, "1000", 0, "1,000"},
+		4:  {LocaleUS, "0.005", 2, "0.00"},
+		5:  {LocaleUS, "999.996", 2, "1,000.00"},
+		6:  {LocaleDE, "-1234567.891", 2, "-1.234.567,89"},
+		7:  {LocaleFR, "-1234.5", 1, "\u22121\u202f234,5"},
+		8:  {LocaleCH, "1e+6", -1, "1'000'000"},
+		9:  {LocaleIN, "1234567.89", -1, "12,34,567.89"},
+		10: {LocaleIN, "123456789012345678901234.5", -1, "1,23,45,67,89,01,23,45,67,89,01,234.5"},
+		11: {LocaleIN, "-12345", 0, "-12,345"},
+		12: {Locale{Group: ",", Grouping: []int{3, 0}}, "1234567", -1, "1234,567"},
+		13: {Locale{Group: " ", Grouping: []int{4}, MinusStyle: MinusTrailing}, "-12345678", -1, "1234 5678-"},
+		14: {Locale{Group: ",", Grouping: []int{3}, MinusStyle: MinusParens}, "-1234.5", 2, "(1,234.50)"},
+		15: {deva, "-1234567.5", 2, "-१२,३४,५६७.५०"},
+		16: {LocaleDE, "-Inf", -1, "-Inf"},
+		17: {Locale{MinusStyle: MinusParens}, "-Inf", -1, "(Inf)"},
+		18: {LocaleDE, "NaN", 2, "NaN"},
+	} {
+		if r := test.l.Format(newbig(t, test.x), test.prec); r != test.r {
+			t.Errorf("#%d: Format(%s, %d) wanted %q, got %q", i, test.x, test.prec, test.r, r)
+		}
+	}
+}
+
+func TestBig_Log(t *testing.T) {
+	for i, test := range [...]struct {
+		x    string
+		res  string
+		prec int32
+		mode RoundingMode
+	}{
+		0:  {"2", "0.6931471805599453", 16, ToNearestEven},
+		1:  {"10", "2.3025850929940456840179914546843642076011014886288", 50, ToNearestEven},
+		2:  {"0.5", "-0.6931471805599453094172321214581766", 34, ToNearestEven},
+		3:  {"1.0000001", "9.999999500000033e-8", 16, ToNearestEven},
+		4:  {"0.999999", "-0.0000010000005000003333335", 20, ToZero},
+		5:  {"123456789.987654321", "18.63140177416801807409394", 25, ToPositiveInf},
+		6:  {"1e-300", "-690.7755278982138", 16, ToNegativeInf},
+		7:  {"1e1000", "2302.585", 7, ToNearestEven},
+		8:  {"0.0625", "-2.772588722", 10, ToNearestEven},
+		9:  {"3", "1.0987", 5, AwayFromZero},
+		10: {"1", "0", 16, ToNearestEven},
+		11: {"Inf", "Inf", 16, ToNearestEven},
+		// An unlimited precision rounds to DefaultPrec digits.
+		12: {"2", "0.6931471805599453", -1, ToNearestEven},
+	} {
+		z := new(Big).SetPrec(test.prec).SetMode(test.mode)
+		if zs := z.Log(newbig(t, test.x)).String(); zs != test.res {
+			t.Errorf("#%d: Log(%s) wanted %s, got %s", i, test.x, test.res, zs)
+		}
+	}
+
+	if z := new(Big).Log(New(0, 0)); !z.IsInf() || !z.SignBit() {
+		t.Errorf("Log(0) wanted -Inf, got %s", z)
+	}
+	if z := new(Big).Log(New(-1, 0)); !z.IsNaN() || z.Payload() != LogNegative {
+		t.Errorf("Log(-1) wanted NaN with payload %d, got %s", LogNegative, z)
+	}
+
+	// The result is brought into the Context's exponent range.
+	for i, test := range [...]struct {
+		ctx Context
+		x   string
+		res string
+		c   Condition
+	}{
+		0: {Context32, "1." + strings.Repeat("0", 199) + "1", "0", Underflow | Subnormal | Inexact | Rounded | Clamped},
+		1: {Context64, "1." + strings.Repeat("0", 389) + "1", "1.00000000e-390", Underflow | Subnormal | Inexact | Rounded},
+		2: {Context32, "1e-100000", "-230258.5", Inexact | Rounded},
+	} {
+		z := new(Big).SetContext(test.ctx)
+		if zs := z.Log(newbig(t, test.x)).String(); zs != test.res || z.Conditions() != test.c {
+			t.Errorf("#%d: Log(%s) wanted %s (%s), got %s (%s)",
+				i, test.x, test.res, test.c, zs, z.Conditions())
+		}
+	}
+}
+
+func TestBig_Log10(t *testing.T) {
+	for i, test := range [...]struct {
+		x    string
+		res  string
+		prec int32
+		mode RoundingMode
+	}{
+		0:  {"2", "0.3010299956639812", 16, ToNearestEven},
+		1:  {"0.5", "-0.301029995663981195213738894724493", 34, ToNearestEven},
+		2:  {"1.0000001", "4.342944601885292e-8", 16, ToNearestEven},
+		3:  {"0.999999", "-4.3429469905063754421e-7", 20, ToZero},
+		4:  {"123456789.987654321", "8.091514980643626320506993", 25, ToPositiveInf},
+		5:  {"3", "0.47713", 5, AwayFromZero},
+		6:  {"1024", "3.010299956639812", 16, ToNearestEven},
+		7:  {"10", "1", 50, ToNearestEven},
+		8:  {"1e-300", "-300", 16, ToNegativeInf},
+		9:  {"1e1000", "1000", 7, ToNearestEven},
+		10: {"0.001", "-3", 16, ToNearestEven},
+		11: {"1", "0", 16, ToNearestEven},
+		12: {"1e1000", "1e+3", 1, ToNearestEven},
+	} {
+		z := new(Big).SetPrec(test.prec).SetMode(test.mode)
+		if zs := z.Log10(newbig(t, test.x)).String(); zs != test.res {
+			t.Errorf("#%d: Log10(%s) wanted %s, got %s", i, test.x, test.res, zs)
+		}
+	}
+}
+
+func TestBig_Log2(t *testing.T) {
+	for i, test := range [...]struct {
+		x    string
+		res  string
+		prec int32
+		mode RoundingMode
+	}{
+		0:  {"10", "3.3219280948873623478703194294893901758648313930246", 50, ToNearestEven},
+		1:  {"1.0000001", "1.442694968754216e-7", 16, ToNearestEven},
+		2:  {"0.999999", "-0.0000014426957622369647505", 20, ToZero},
+		3:  {"123456789.987654321", "26.87943094440203420718182", 25, ToPositiveInf},
+		4:  {"1e-300", "-996.5784284662088", 16, ToNegativeInf},
+		5:  {"0.001", "-9.965784284662087", 16, ToNearestEven},
+		6:  {"3", "1.585", 5, AwayFromZero},
+		7:  {"2", "1", 16, ToNearestEven},
+		8:  {"0.5", "-1", 34, ToPositiveInf},
+		9:  {"1024", "10", 16, ToNegativeInf},
+		10: {"0.0625", "-4", 10, ToNearestEven},
+		11: {"16777216", "24", 34, ToNegativeInf},
+		12: {"0.00390625", "-8", 20, ToZero},
+		13: {"1", "0", 16, ToNearestEven},
+	} {
+		z := new(Big).SetPrec(test.prec).SetMode(test.mode)
+		if zs := z.Log2(newbig(t, test.x)).String(); zs != test.res {
+			t.Errorf("#%d: Log2(%s) wanted %s, got %s", i, test.x, test.res, zs)
+		}
+	}
+}
+
 // func TestBig_Format(t *testing.T) {
 // 	tests := [...]struct {
 // 		format string
@@ -332,6 +1236,92 @@ func TestBig_IsInt(t *testing.T) {
 // 	}
 // }
 
+func TestBig_Logb(t *testing.T) {
+	for i, test := range [...]struct {
+		x, res string
+	}{
+		0: {"250", "2"},
+		1: {"2.50", "0"},
+		2: {"0.03", "-2"},
+		3: {"-1e+10", "10"},
+		4: {"12345678901234567890.5", "19"},
+		5: {"1e-2147483647", "-2147483647"},
+		6: {"Inf", "Inf"},
+	} {
+		if zs := new(Big).Logb(newbig(t, test.x)).String(); zs != test.res {
+			t.Errorf("#%d: Logb(%s) wanted %s, got %s", i, test.x, test.res, zs)
+		}
+	}
+	if z := new(Big).Logb(new(Big)); !z.IsInf() || !z.SignBit() || z.Conditions() != DivisionByZero {
+		t.Errorf("Logb(0) wanted -Inf (%s), got %s (%s)", DivisionByZero, z, z.Conditions())
+	}
+}
+
+func TestMax(t *testing.T) {
+	for i, test := range [...]struct {
+		x                        []string
+		max, maxMag, min, minMag int
+	}{
+		0: {[]string{"1", "2", "3"}, 2, 2, 0, 0},
+		1: {[]string{"-3", "2", "1"}, 1, 0, 0, 2},
+		2: {[]string{"1.0", "1", "1.00"}, 1, 1, 2, 2},
+		3: {[]string{"-1.0", "-1", "-1.00"}, 2, 2, 1, 1},
+		4: {[]string{"-2", "2"}, 1, 1, 0, 0},
+		5: {[]string{"1", "1"}, 0, 0, 0, 0},
+		6: {[]string{"1e+3", "1000", "999"}, 0, 0, 2, 2},
+		7: {[]string{"Inf", "12345678901234567890", "0"}, 0, 0, 2, 2},
+		8: {[]string{"-12345678901234567890.1", "-12345678901234567890"}, 1, 0, 0, 1},
+		9: {[]string{"0.5", "-0.25"}, 0, 0, 1, 1},
+	} {
+		x := make([]*Big, len(test.x))
+		for j, s := range test.x {
+			x[j] = newbig(t, s)
+		}
+		for _, fn := range [...]struct {
+			name string
+			f    func(...*Big) *Big
+			want int
+		}{
+			{"Max", Max, test.max},
+			{"MaxMag", MaxMag, test.maxMag},
+			{"Min", Min, test.min},
+			{"MinMag", MinMag, test.minMag},
+		} {
+			if r := fn.f(x...); r != x[fn.want] {
+				t.Errorf("#%d: %s(%v) wanted %s, got %s", i, fn.name, test.x, x[fn.want], r)
+			}
+		}
+	}
+	if Max() != nil || MinMag() != nil {
+		t.Error("wanted nil for no arguments")
+	}
+
+	// A signaling NaN is quieted and raises InvalidOperation.
+	for i, x := range [...][]string{
+		0: {"1", "sNaN7", "sNaN8"},
+		1: {"NaN1", "sNaN7", "2"},
+		2: {"sNaN7"},
+	} {
+		v := make([]*Big, len(x))
+		for j, s := range x {
+			v[j] = newbig(t, s)
+		}
+		for _, f := range [...]func(...*Big) *Big{Max, MaxMag, Min, MinMag} {
+			r := f(v...)
+			if !r.IsNaN() || r.IsSignaling() || r.Payload() != 7 ||
+				r.Conditions() != InvalidOperation {
+				t.Errorf("#%d: %v wanted NaN7 (%s), got %s (%s)",
+					i, x, InvalidOperation, r, r.Conditions())
+			}
+			for _, s := range v {
+				if r == s {
+					t.Errorf("#%d: %v returned an operand", i, x)
+				}
+			}
+		}
+	}
+}
+
 func TestBig_Neg(t *testing.T) {
 	tests := [...]struct {
 		a, b *Big
@@ -351,6 +1341,70 @@ func TestBig_Neg(t *testing.T) {
 	}
 }
 
+func TestBig_NextAfter(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y string
+		prec int32
+		res  string
+	}{
+		0: {"1", "2", 3, "1.01"},
+		1: {"1", "0", 3, "0.999"},
+		2: {"1", "1", 3, "1"},
+		3: {"-1", "Inf", 3, "-0.999"},
+		4: {"0", "-5", 16, "-1e-2147483647"},
+		5: {"1.2345", "-10", 3, "1.23"},
+		6: {"Inf", "Inf", 3, "Inf"},
+		7: {"Inf", "1", 2, "9.9e+2147483649"},
+	} {
+		z := new(Big).SetPrec(test.prec)
+		if zs := z.NextAfter(newbig(t, test.x), newbig(t, test.y)).String(); zs != test.res {
+			t.Errorf("#%d: NextAfter(%s, %s) wanted %s, got %s", i, test.x, test.y, test.res, zs)
+		}
+	}
+}
+
+func TestBig_NextUp(t *testing.T) {
+	for i, test := range [...]struct {
+		x        string
+		ctx      Context
+		up, down string
+	}{
+		0:  {"1", Context{Prec: 3}, "1.01", "0.999"},
+		1:  {"-1", Context{Prec: 3}, "-0.999", "-1.01"},
+		2:  {"1000", Context{Prec: 3}, "1.01e+3", "999"},
+		3:  {"0.100", Context{Prec: 2}, "0.11", "0.099"},
+		4:  {"1.2345", Context{Prec: 3}, "1.24", "1.23"},
+		5:  {"-1.2345", Context{Prec: 3}, "-1.23", "-1.24"},
+		6:  {"999", Context{Prec: 3}, "1.00e+3", "998"},
+		7:  {"9.99e+5", Context{Prec: 3}, "1.00e+6", "9.98e+5"},
+		8:  {"5183509474513890000000000000", Context{Prec: 16}, "5.183509474513891e+27", "5.183509474513889e+27"},
+		9:  {"12345678901234567890.12345", Context{Prec: 34}, "12345678901234567890.12345000000001", "12345678901234567890.12344999999999"},
+		10: {"0", Context{Prec: 16}, "1e-2147483647", "-1e-2147483647"},
+		11: {"1e-2147483647", Context{Prec: 16}, "2e-2147483647", "0"},
+		12: {"Inf", Context{Prec: 3}, "Inf", "9.99e+2147483650"},
+		13: {"0", Context64, "1e-398", "-1e-398"},
+		14: {"1", Context64, "1.000000000000001", "0.9999999999999999"},
+		15: {"Inf", Context64, "Inf", "9.999999999999999e+384"},
+		16: {"-Inf", Context64, "-9.999999999999999e+384", "-Inf"},
+		17: {"9.999999999999999e+384", Context64, "Inf", "9.999999999999998e+384"},
+		18: {"1e-398", Context64, "2e-398", "0"},
+		19: {"1e-383", Context64, "1.000000000000001e-383", "9.99999999999999e-384"},
+		20: {"1e-500", Context64, "1e-398", "0"},
+		21: {"0", Context32, "1e-101", "-1e-101"},
+		22: {"1e+384", Context32, "Inf", "9.999999e+96"},
+		23: {"1e-383", Context32, "1e-101", "0"},
+		24: {"123.45678901234567890", Context32, "123.4568", "123.4567"},
+	} {
+		x := newbig(t, test.x)
+		if s := new(Big).SetContext(test.ctx).NextUp(x).String(); s != test.up {
+			t.Errorf("#%d: NextUp(%s) wanted %s, got %s", i, test.x, test.up, s)
+		}
+		if s := new(Big).SetContext(test.ctx).NextDown(x).String(); s != test.down {
+			t.Errorf("#%d: NextDown(%s) wanted %s, got %s", i, test.x, test.down, s)
+		}
+	}
+}
+
 func TestBig_Modf(t *testing.T) {
 	tests := [...]struct {
 		dec  string
@@ -433,10 +1487,251 @@ func TestBig_Mul(t *testing.T) {
 	}
 }
 
+func TestPattern_Format(t *testing.T) {
+	for i, test := range [...]struct {
+		p    string
+		mode RoundingMode
+		x    string
+		r    string
+	}{
+		0:  {"#,##0.00;(#,##0.00)", ToNearestEven, "1234567.891", "1,234,567.89"},
+		1:  {"#,##0.00;(#,##0.00)", ToNearestEven, "-1234.5", "(1,234.50)"},
+		2:  {"#,##0.00", ToNearestEven, "-1234.5", "-1,234.50"},
+		3:  {"#,##0.00", ToNearestEven, "-0.001", "-0.00"},
+		4:  {"#,##,##0.##", ToNearestEven, "123456789.5", "12,34,56,789.5"},
+		5:  {"0.00;0.00-", ToNearestEven, "-1.5", "1.50-"},
+		6:  {"0.0", ToZero, "1.99", "1.9"},
+		7:  {"0.0", ToNegativeInf, "-1.25", "-1.3"},
+		8:  {"0.00", ToNearestEven, "123456789012345678901234.567", "123456789012345678901234.57"},
+		9:  {"#.##", ToNearestEven, "0", "0"},
+		10: {"#.##", ToNearestEven, "0.5", ".5"},
+		11: {"0.##", ToNearestEven, "1", "1"},
+		12: {"000", ToNearestEven, "7", "007"},
+		13: {"0.00%", ToNearestEven, "0.12345", "12.34%"},
+		14: {"0.00%", ToNearestAway, "0.12345", "12.35%"},
+		15: {"#,##0‰", ToNearestEven, "1.2345", "1,234‰"},
+		16: {"0.###E0", ToNearestEven, "12345", "1.234E4"},
+		17: {"0.###E0", ToNearestEven, "0.00012", "1.2E-4"},
+		18: {"00.###E0", ToNearestEven, "0.00123", "12.3E-4"},
+		19: {"##0.##E0", ToNearestEven, "12345", "12.3E3"},
+		20: {"##0.##E0", ToNearestEven, "0.00123", "1.23E-3"},
+		21: {"##0.##E0", ToNearestEven, "123456", "123E3"},
+		22: {"##0.##E0", ToNearestEven, "999.6", "1E3"},
+		23: {"0.00E+00", ToNearestEven, "1234", "1.23E+03"},
+		24: {"0.00E+00", ToNearestEven, "-0", "-0.00E+00"},
+		25: {"*x#,##0.00", ToNearestEven, "12.3", "xxx12.30"},
+		26: {"$*x#,##0.00", ToNearestEven, "12.3", "$xxx12.30"},
+		27: {"#,##0.00*_;(#,##0.00)", ToNearestEven, "-12.3", "(12.30_)"},
+		28: {"* #,##0.00", ToNearestEven, "123456789", "123,456,789.00"},
+		29: {"0.00 'EUR'", ToNearestEven, "1.5", "1.50 EUR"},
+		30: {"'#'0", ToNearestEven, "7", "#7"},
+		31: {"0 o''clock", ToNearestEven, "5", "5 o'clock"},
+		32: {"'It''s' 0", ToNearestEven, "5", "It's 5"},
+		33: {"0.00;(0.00)", ToNearestEven, "-Inf", "(Inf)"},
+		34: {"0.00", ToNearestEven, "NaN", "NaN"},
+		35: {"*'##0", ToNearestEven, "5", "''5"},
+		36: {"0*;;-0", ToNearestEven, "-5", "-5"},
+	} {
+		p, err := CompilePattern(test.p)
+		if err != nil {
+			t.Errorf("#%d: %v", i, err)
+			continue
+		}
+		x := newbig(t, test.x).SetMode(test.mode)
+		if r := p.Format(x); r != test.r {
+			t.Errorf("#%d: Format(%q, %s) wanted %q, got %q", i, test.p, test.x, test.r, r)
+		}
+		if c := x.Conditions(); c != 0 {
+			t.Errorf("#%d: Format(%q, %s) raised %s", i, test.p, test.x, c)
+		}
+	}
+
+	// x's precision and exponent limits don't apply.
+	x := newbig(t, "0.123456789").SetContext(Context32)
+	if r, want := MustCompilePattern("0.00000000%").Format(x), "12.34567890%"; r != want {
+		t.Errorf("Format(0.00000000%%, %s) wanted %q, got %q", x, want, r)
+	}
+
+	p := MustCompilePattern("#,##0.00")
+	if r, want := p.FormatLocale(newbig(t, "-1234.5"), LocaleDE), "-1.234,50"; r != want {
+		t.Errorf("FormatLocale(%q, -1234.5) wanted %q, got %q", p, want, r)
+	}
+	p = MustCompilePattern("0.0E0")
+	if r, want := p.FormatLocale(newbig(t, "-0.00012"), LocaleFR), "\u22121,2E\u22124"; r != want {
+		t.Errorf("FormatLocale(%q, -0.00012) wanted %q, got %q", p, want, r)
+	}
+
+	for _, s := range [...]string{
+		"", "abc", "#0#", "0.#0", "0.0.0", "#,##0.0,0", "#,##0,.00", "0E",
+		"#,##0E0", "0;0;0", "'0", "0;", "*", "0*", "0 *xabc", "*x*y0",
+		"0%%", "1.00", "@@", "#0 #", "#*''", "*''-", "*'x' ", "x0*''E",
+	} {
+		if _, err := CompilePattern(s); err == nil {
+			t.Errorf("CompilePattern(%q): wanted an error", s)
+		}
+	}
+}
+
+func TestBig_Pow(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y string
+		res  string
+		prec int32
+		mode RoundingMode
+	}{
+		0:  {"2", "10", "1024", 16, ToNearestEven},
+		1:  {"2", "-10", "0.0009765625", 16, ToNearestEven},
+		2:  {"-2", "3", "-8", 16, ToNearestEven},
+		3:  {"-2", "-3", "-0.125", 16, ToNearestEven},
+		4:  {"1.5", "2", "2", 1, ToNearestEven},
+		5:  {"2.5", "2", "6.2", 2, ToNearestEven},
+		6:  {"2.5", "2", "6.3", 2, ToNearestAway},
+		7:  {"1.1", "100", "13780.61233982227", 16, ToNearestEven},
+		8:  {"1.0001", "365", "1.037172411302551929902028017056329", 34, ToNearestEven},
+		9:  {"3", "-1", "0.3333333333333333333333333333333334", 34, AwayFromZero},
+		10: {"3", "-1", "0.3333333333333333333333333333333333", 34, ToZero},
+		11: {"7", "-2", "0.020408163265306122448", 20, ToNegativeInf},
+		12: {"-7", "-3", "-0.0029154518950437317784", 20, ToPositiveInf},
+		13: {"-1.5", "7", "-17.0859375", 16, ToNearestEven},
+		14: {"1.0425", "0.3333333333333333333333333333333333", "1.013970580630922723585007085286435", 34, ToNearestEven},
+		15: {"2", "0.5", "1.4142135623730950488016887242096980785696718753769", 50, ToNearestEven},
+		16: {"10", "0.5", "3.162277660168379", 16, ToZero},
+		17: {"0.25", "0.5", "0.5", 16, ToNearestEven},
+		18: {"9", "1.5", "27", 16, ToNearestEven},
+		19: {"1.05", "365.25", "54877142.006512709834", 20, ToNearestAway},
+		20: {"123.456", "-2.5", "0.000005904994479458169", 16, ToPositiveInf},
+		21: {"1e-10", "1.5", "1e-15", 16, ToNearestEven},
+		22: {"12345.6789", "0.001", "1.009465579285190721549425", 25, ToNegativeInf},
+		23: {"0", "0", "1", 16, ToNearestEven},
+		24: {"Inf", "0", "1", 16, ToNearestEven},
+		25: {"1", "Inf", "1", 16, ToNearestEven},
+		26: {"0", "2.5", "0", 16, ToNearestEven},
+		27: {"0", "-3", "Inf", 16, ToNearestEven},
+		28: {"Inf", "-0.5", "0", 16, ToNearestEven},
+		29: {"Inf", "2", "Inf", 16, ToNearestEven},
+		30: {"0.5", "Inf", "0", 16, ToNearestEven},
+		31: {"-2", "Inf", "Inf", 16, ToNearestEven},
+		32: {"2", "1e100", "Inf", 16, ToNearestEven},
+		33: {"0.5", "1e100", "0", 16, ToNearestEven},
+		// An unlimited precision rounds to DefaultPrec digits unless y is a
+		// non-negative integer.
+		34: {"2", "0.5", "1.414213562373095", -1, ToNearestEven},
+		35: {"3", "-1", "0.3333333333333333", -1, ToNearestEven},
+		36: {"2", "-3", "0.125", -1, ToNearestEven},
+		37: {"1.5", "40", "11057332.3209400121422731899656355381011962890625", -1, ToNearestEven},
+	} {
+		z := new(Big).SetPrec(test.prec).SetMode(test.mode)
+		if zs := z.Pow(newbig(t, test.x), newbig(t, test.y)).String(); zs != test.res {
+			t.Errorf("#%d: Pow(%s, %s) wanted %s, got %s", i, test.x, test.y, test.res, zs)
+		}
+	}
+
+	if z := new(Big).Pow(New(-2, 0), New(5, 1)); !z.IsNaN() || z.Payload() != PowNegNonInt {
+		t.Errorf("Pow(-2, 0.5) wanted NaN with payload %d, got %s", PowNegNonInt, z)
+	}
+
+	// The result is brought into the Context's exponent range.
+	for i, test := range [...]struct {
+		ctx  Context
+		mode RoundingMode
+		x, y string
+		res  string
+		c    Condition
+	}{
+		0: {Context64, ToNearestEven, "10", "500", "Inf", Overflow | Inexact | Rounded},
+		1: {Context64, ToZero, "10", "500", "9.999999999999999e+384", Overflow | Inexact | Rounded},
+		2: {Context64, ToNearestEven, "10", "-500", "0", Underflow | Subnormal | Inexact | Rounded | Clamped},
+		3: {Context32, ToNearestEven, "-0.1", "101", "-1e-101", Subnormal},
+		4: {Context32, ToNearestEven, "2", "1000.5", "Inf", Overflow | Inexact | Rounded},
+		5: {Context32, ToPositiveInf, "0.5", "1000.5", "1e-101", Underflow | Subnormal | Inexact | Rounded},
+	} {
+		z := new(Big).SetContext(test.ctx).SetMode(test.mode)
+		x, y := newbig(t, test.x), newbig(t, test.y)
+		if zs := z.Pow(x, y).String(); zs != test.res || z.Conditions() != test.c {
+			t.Errorf("#%d: Pow(%s, %s) wanted %s (%s), got %s (%s)",
+				i, test.x, test.y, test.res, test.c, zs, z.Conditions())
+		}
+	}
+}
+
 func TestBig_Prec(t *testing.T) {
 	// confirmed to work inside internal/arith/intlen_test.go
 }
 
+func TestBig_Quantize(t *testing.T) {
+	for i, test := range [...]struct {
+		x     string
+		scale int32
+		res   string
+		prec  int32
+		mode  RoundingMode
+	}{
+		0:  {"1.235", 2, "1.24", 16, ToNearestEven},
+		1:  {"1.245", 2, "1.24", 16, ToNearestEven},
+		2:  {"1.245", 2, "1.25", 16, ToNearestAway},
+		3:  {"-1.245", 2, "-1.25", 16, ToNegativeInf},
+		4:  {"-1.245", 2, "-1.24", 16, ToPositiveInf},
+		5:  {"1.2", 3, "1.2", 16, ToNearestEven},
+		6:  {"123.456", -1, "1.2e+2", 16, ToNearestEven},
+		7:  {"123.456", -2, "2e+2", 16, AwayFromZero},
+		8:  {"0.004", 2, "0", 16, ToNearestEven},
+		9:  {"0.004", 2, "0.01", 16, AwayFromZero},
+		10: {"-0.004", 2, "-0.01", 16, ToNegativeInf},
+		11: {"9.995", 2, "10", 16, ToNearestEven},
+		12: {"12345678901234567890.123456789", 2, "12345678901234567890.12", 34, ToNearestEven},
+		13: {"12345678901234567890.125", 2, "12345678901234567890.12", 34, ToNearestEven},
+		14: {"12345678901234567890.125", 2, "12345678901234567890.13", 34, ToNearestAway},
+		15: {"1e-100", 2, "0.01", 16, ToPositiveInf},
+		16: {"1e-100", 2, "0", 16, ToZero},
+		17: {"3", 18, "3", 20, ToNearestEven},
+		18: {"0", 2, "0", 16, ToNearestEven},
+	} {
+		z := new(Big).SetPrec(test.prec).SetMode(test.mode)
+		z.Quantize(newbig(t, test.x), test.scale)
+		if zs := z.String(); zs != test.res {
+			t.Errorf("#%d: Quantize(%s, %d) wanted %s, got %s", i, test.x, test.scale, test.res, zs)
+		}
+		if z.Scale() != test.scale {
+			t.Errorf("#%d: Quantize(%s, %d) wanted scale %d, got %d",
+				i, test.x, test.scale, test.scale, z.Scale())
+		}
+	}
+
+	for i, test := range [...]struct {
+		x     string
+		scale int32
+		prec  int32
+		p     Payload
+		c     Condition
+	}{
+		{"123456789", 5, 10, QuantizeMinMax, InvalidOperation},
+		{"9.995", 2, 3, QuantizeMinMax, InvalidOperation},
+		{"999.999", 2, 5, QuantizeMinMax, InvalidOperation},
+		{"Inf", 2, 16, QuantizeInf, InvalidOperation},
+		{"NaN7", 2, 16, 7, 0},
+	} {
+		z := new(Big).SetPrec(test.prec).Quantize(newbig(t, test.x), test.scale)
+		if !z.IsNaN() || z.Payload() != test.p {
+			t.Errorf("#%d: Quantize(%s, %d) wanted NaN with payload %d, got %s", i, test.x, test.scale, test.p, z)
+		}
+		if z.Conditions() != test.c {
+			t.Errorf("#%d: Quantize(%s, %d) wanted %s, got %s", i, test.x, test.scale, test.c, z.Conditions())
+		}
+	}
+
+	// Inexact is only raised if the result fits.
+	z := new(Big).SetPrec(5).SetTraps(Inexact)
+	if didPanic(func() { z.Quantize(newbig(t, "999.999"), 2) }) {
+		t.Error("Quantize(999.999, 2): wanted no panic(Inexact)")
+	}
+	if !didPanic(func() { z.Quantize(newbig(t, "99.999"), 2) }) {
+		t.Error("Quantize(99.999, 2): wanted panic(Inexact)")
+	}
+	if c := z.Conditions(); c != InvalidOperation|Inexact|Rounded {
+		t.Errorf("Quantize: wanted %s, got %s", InvalidOperation|Inexact|Rounded, c)
+	}
+}
+
 func TestBig_Quo(t *testing.T) {
 	huge1, ok := new(Big).SetString("12345678901234567890.1234")
 	if !ok {
@@ -488,6 +1783,77 @@ func TestBig_Quo(t *testing.T) {
 	}
 }
 
+func TestBig_QuoRem(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y string
+		quo  string // QuoInt
+		rem  string // Rem
+		near string // RemNear
+		mod  string // Mod
+	}{
+		0:  {"7", "3", "2", "1", "1", "1"},
+		1:  {"-7", "3", "-2", "-1", "-1", "2"},
+		2:  {"7", "-3", "-2", "1", "1", "1"},
+		3:  {"-7", "-3", "2", "-1", "-1", "2"},
+		4:  {"10", "0.25", "40", "0", "0", "0"},
+		5:  {"10.3", "0.25", "41", "0.05", "0.05", "0.05"},
+		6:  {"-10.3", "0.25", "-41", "-0.05", "-0.05", "0.2"},
+		7:  {"1.1", "0.2", "5", "0.1", "-0.1", "0.1"},
+		8:  {"5", "2", "2", "1", "1", "1"},
+		9:  {"7", "2", "3", "1", "-1", "1"},
+		10: {"-5", "2", "-2", "-1", "-1", "1"},
+		11: {"0.75", "0.5", "1", "0.25", "-0.25", "0.25"},
+		12: {"123456789012345678901234567890.5", "0.25", "493827156049382715604938271562", "0", "0", "0"},
+		13: {"-123456789012345678901234567890.5", "7", "-17636684144620811271604938270", "-0.5", "-0.5", "6.5"},
+		14: {"9223372036854775807", "-1", "-9223372036854775807", "0", "0", "0"},
+		15: {"-9223372036854775808", "-1", "9223372036854775808", "-0", "-0", "0"},
+		16: {"1e-20", "3", "0", "1e-20", "1e-20", "1e-20"},
+		17: {"1e20", "0.3", "333333333333333333333", "0.1", "0.1", "0.1"},
+		18: {"2.5", "1e-30", "2500000000000000000000000000000", "0", "0", "0"},
+		19: {"3", "1e40", "0", "3", "3", "3"},
+		20: {"0", "3", "0", "0", "0", "0"},
+		21: {"3", "Inf", "0", "3", "3", "3"},
+	} {
+		x, y := newbig(t, test.x), newbig(t, test.y)
+		if s := new(Big).QuoInt(x, y).String(); s != test.quo {
+			t.Errorf("#%d: QuoInt(%s, %s) wanted %s, got %s", i, test.x, test.y, test.quo, s)
+		}
+		if s := new(Big).Rem(x, y).String(); s != test.rem {
+			t.Errorf("#%d: Rem(%s, %s) wanted %s, got %s", i, test.x, test.y, test.rem, s)
+		}
+		if s := new(Big).RemNear(x, y).String(); s != test.near {
+			t.Errorf("#%d: RemNear(%s, %s) wanted %s, got %s", i, test.x, test.y, test.near, s)
+		}
+		if s := new(Big).Mod(x, y).String(); s != test.mod {
+			t.Errorf("#%d: Mod(%s, %s) wanted %s, got %s", i, test.x, test.y, test.mod, s)
+		}
+		q, r := new(Big).QuoRem(x, y, new(Big))
+		if qs, rs := q.String(), r.String(); qs != test.quo || rs != test.rem {
+			t.Errorf("#%d: QuoRem(%s, %s) wanted (%s, %s), got (%s, %s)",
+				i, test.x, test.y, test.quo, test.rem, qs, rs)
+		}
+		// The operands must not be modified.
+		if xs, ys := x.String(), y.String(); xs != newbig(t, test.x).String() ||
+			ys != newbig(t, test.y).String() {
+			t.Errorf("#%d: operands modified: got (%s, %s)", i, xs, ys)
+		}
+	}
+
+	for i, test := range [...]struct{ x, y string }{
+		{"Inf", "2"},
+		{"2", "0"},
+		{"0", "0"},
+	} {
+		x, y := newbig(t, test.x), newbig(t, test.y)
+		if z := new(Big).Rem(x, y); !z.IsNaN() || z.Payload() != RemInfOrZero {
+			t.Errorf("#%d: Rem(%s, %s) wanted NaN with payload %d, got %s", i, test.x, test.y, RemInfOrZero, z)
+		}
+	}
+	if s := new(Big).QuoInt(New(2, 0), New(0, 0)).String(); s != "Inf" {
+		t.Errorf("QuoInt(2, 0) wanted Inf, got %s", s)
+	}
+}
+
 func TestBig_Round(t *testing.T) {
 	for i, test := range [...]struct {
 		v   string
@@ -509,6 +1875,203 @@ func TestBig_Round(t *testing.T) {
 	}
 }
 
+func TestBig_RoundToInt(t *testing.T) {
+	for i, test := range [...]struct {
+		x                  string
+		floor, ceil, trunc string
+		even               string // RoundToInt with ToNearestEven
+	}{
+		0:  {"2.5", "2", "3", "2", "2"},
+		1:  {"3.5", "3", "4", "3", "4"},
+		2:  {"-2.5", "-3", "-2", "-2", "-2"},
+		3:  {"0.5", "0", "1", "0", "0"},
+		4:  {"-0.5", "-1", "-0", "-0", "-0"},
+		5:  {"1.0001", "1", "2", "1", "1"},
+		6:  {"-1.0001", "-2", "-1", "-1", "-1"},
+		7:  {"12345678901234567890.999", "12345678901234567890", "12345678901234567891", "12345678901234567890", "12345678901234567891"},
+		8:  {"-12345678901234567890.999", "-12345678901234567891", "-12345678901234567890", "-12345678901234567890", "-12345678901234567891"},
+		9:  {"1e+5", "1e+5", "1e+5", "1e+5", "1e+5"},
+		10: {"7", "7", "7", "7", "7"},
+		11: {"0", "0", "0", "0", "0"},
+		12: {"Inf", "Inf", "Inf", "Inf", "Inf"},
+	} {
+		x := newbig(t, test.x)
+		if s := new(Big).Floor(x).String(); s != test.floor {
+			t.Errorf("#%d: Floor(%s) wanted %s, got %s", i, test.x, test.floor, s)
+		}
+		if s := new(Big).Ceil(x).String(); s != test.ceil {
+			t.Errorf("#%d: Ceil(%s) wanted %s, got %s", i, test.x, test.ceil, s)
+		}
+		if s := new(Big).Trunc(x).String(); s != test.trunc {
+			t.Errorf("#%d: Trunc(%s) wanted %s, got %s", i, test.x, test.trunc, s)
+		}
+		if s := new(Big).RoundToInt(x).String(); s != test.even {
+			t.Errorf("#%d: RoundToInt(%s) wanted %s, got %s", i, test.x, test.even, s)
+		}
+		z, exact := new(Big).SetMode(ToZero).RoundToIntExact(x)
+		if s := z.String(); s != test.trunc || exact != (test.trunc == test.x) {
+			t.Errorf("#%d: RoundToIntExact(%s) wanted (%s, %t), got (%s, %t)",
+				i, test.x, test.trunc, test.trunc == test.x, s, exact)
+		}
+	}
+
+	// Only RoundToIntExact raises Inexact, so only it can trap it. Floor,
+	// Ceil and Trunc have explicit modes, so Unneeded doesn't trap either.
+	for i, test := range [...]struct {
+		op    string
+		traps Condition
+		mode  RoundingMode
+		r     string
+		want  Condition // panic value, or 0 if op shouldn't panic
+	}{
+		0: {"RoundToInt", Inexact, ToNearestEven, "2", 0},
+		1: {"Floor", Inexact | Rounded, ToNearestEven, "1", 0},
+		2: {"Ceil", Inexact, ToNearestEven, "2", 0},
+		3: {"Trunc", Inexact, ToNearestEven, "1", 0},
+		4: {"Floor", 0, Unneeded, "1", 0},
+		5: {"RoundToIntExact", Inexact, ToZero, "", Inexact},
+	} {
+		func() {
+			z := new(Big).SetTraps(test.traps).SetMode(test.mode)
+			defer func() {
+				r := recover()
+				if c, _ := r.(Condition); c != test.want || r != nil && c == 0 {
+					t.Errorf("#%d: %s(1.5) wanted panic(%s), got panic(%v)", i, test.op, test.want, r)
+				}
+			}()
+			x := newbig(t, "1.5")
+			switch test.op {
+			case "RoundToInt":
+				z.RoundToInt(x)
+			case "Floor":
+				z.Floor(x)
+			case "Ceil":
+				z.Ceil(x)
+			case "Trunc":
+				z.Trunc(x)
+			case "RoundToIntExact":
+				z.RoundToIntExact(x)
+			}
+			if s := z.String(); s != test.r {
+				t.Errorf("#%d: %s(1.5) wanted %s, got %s", i, test.op, test.r, s)
+			}
+			if c := z.Conditions(); c != 0 {
+				t.Errorf("#%d: %s(1.5) raised %s", i, test.op, c)
+			}
+		}()
+	}
+}
+
+func TestBig_RoundToScale(t *testing.T) {
+	for i, test := range [...]struct {
+		x     string
+		scale int32
+		mode  RoundingMode
+		res   string
+	}{
+		0:  {"1.2345", 2, ToNearestEven, "1.23"},
+		1:  {"1.2350", 2, ToNearestEven, "1.24"},
+		2:  {"1.2450", 2, ToNearestEven, "1.24"},
+		3:  {"1.2450", 2, ToNearestAway, "1.25"},
+		4:  {"-1.2450", 2, ToNegativeInf, "-1.25"},
+		5:  {"-1.2450", 2, ToPositiveInf, "-1.24"},
+		6:  {"-1.2450", 2, ToZero, "-1.24"},
+		7:  {"1.2001", 2, AwayFromZero, "1.21"},
+		8:  {"12345.678", -2, ToNearestEven, "1.23e+4"},
+		9:  {"12355", -1, ToNearestEven, "1.236e+4"},
+		10: {"-98765", -3, ToPositiveInf, "-9.8e+4"},
+		11: {"0.0049", 2, ToNearestEven, "0"},
+		12: {"0.0049", 2, AwayFromZero, "0.01"},
+		13: {"12345678901234567890.12345678901234567890", 5, ToNearestEven, "12345678901234567890.12346"},
+		14: {"-12345678901234567890.5", 0, ToNearestEven, "-12345678901234567890"},
+		15: {"99.995", 2, ToNearestEven, "100"},
+		16: {"1.5e-30", 2, ToPositiveInf, "0.01"},
+		17: {"1.5", 2, ToZero, "1.5"},
+		18: {"1.2450", 2, ToNearestZero, "1.24"},
+		19: {"1.2451", 2, ToNearestZero, "1.25"},
+		20: {"-1.2350", 2, ToNearestZero, "-1.23"},
+		21: {"-12345678901234567890.5", 0, ToNearestZero, "-12345678901234567890"},
+		22: {"1.2001", 2, AwayFromZero05, "1.21"},
+		23: {"1.2401", 2, AwayFromZero05, "1.24"},
+		24: {"-1.2501", 2, AwayFromZero05, "-1.26"},
+		25: {"1.2500", 2, AwayFromZero05, "1.25"},
+		26: {"12345678901234567890.12345678901234567890", 5, AwayFromZero05, "12345678901234567890.12346"},
+		27: {"1.5e-30", 2, AwayFromZero05, "0.01"},
+		28: {"1.001", 2, Ceiling, "1.01"},
+		29: {"-1.001", 2, Floor, "-1.01"},
+	} {
+		z := newbig(t, test.x).RoundToScale(test.scale, test.mode)
+		if zs := z.String(); zs != test.res {
+			t.Errorf("#%d: RoundToScale(%s, %d, %s) wanted %s, got %s",
+				i, test.x, test.scale, test.mode, test.res, zs)
+		}
+	}
+}
+
+func TestBig_SameQuantum(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y string
+		same bool
+	}{
+		{"1.23", "4.56", true},
+		{"1.23", "4.5", false},
+		{"1.20", "1.2", false},
+		{"1e+3", "2e+3", true},
+		{"1e+3", "1000", false},
+		{"Inf", "Inf", true},
+		{"Inf", "1", false},
+	} {
+		x, y := newbig(t, test.x), newbig(t, test.y)
+		if same := x.SameQuantum(y); same != test.same {
+			t.Errorf("#%d: %s.SameQuantum(%s) wanted %t, got %t", i, test.x, test.y, test.same, same)
+		}
+	}
+}
+
+func TestBig_Scalb(t *testing.T) {
+	for i, test := range [...]struct {
+		x   string
+		n   int32
+		res string
+	}{
+		0: {"1.23", 2, "123"},
+		1: {"123", -2, "1.23"},
+		2: {"-12345678901234567890", -10, "-1234567890.123456789"},
+		3: {"5", 0, "5"},
+		4: {"0", 5, "0"},
+		5: {"Inf", -5, "Inf"},
+		6: {"1e-2147483647", -2, "0"},
+		7: {"1", math.MaxInt32, "1e+2147483647"},
+	} {
+		if zs := new(Big).Scalb(newbig(t, test.x), test.n).String(); zs != test.res {
+			t.Errorf("#%d: Scalb(%s, %d) wanted %s, got %s", i, test.x, test.n, test.res, zs)
+		}
+	}
+	if z := new(Big).Scalb(newbig(t, "-1e+2147483647"), 10); !z.IsInf() || !z.SignBit() {
+		t.Errorf("Scalb(-1e+2147483647, 10) wanted -Inf, got %s", z)
+	}
+
+	// Context32's exponent limits apply.
+	for i, test := range [...]struct {
+		x   string
+		n   int32
+		res string
+		c   Condition
+	}{
+		0: {"1e+96", 1, "Inf", Overflow | Inexact | Rounded},
+		1: {"1e-95", -7, "0", Underflow | Subnormal | Inexact | Rounded | Clamped},
+		2: {"1.5", -100, "1.5e-100", Subnormal},
+		3: {"1.23456789", 3, "1234.568", Inexact | Rounded},
+		4: {"1", 90, "1e+90", 0},
+	} {
+		z := new(Big).SetContext(Context32).Scalb(newbig(t, test.x), test.n)
+		if zs := z.String(); zs != test.res || z.Conditions() != test.c {
+			t.Errorf("#%d: Scalb(%s, %d) wanted %s (%s), got %s (%s)",
+				i, test.x, test.n, test.res, test.c, zs, z.Conditions())
+		}
+	}
+}
+
 func TestBig_SetFloat64(t *testing.T) {
 	tests := map[float64]string{
 		123.4:          "123.4",
@@ -535,8 +2098,8 @@ func TestBig_SetFloat64(t *testing.T) {
 		}
 	}
 
-	if !didPanic(func() { d.SetFloat64(math.NaN()) }) {
-		t.Fatalf("wanted panic when creating a Big from NaN, got %s instead",
+	if !d.SetFloat64(math.NaN()).IsNaN() {
+		t.Fatalf("wanted NaN when creating a Big from NaN, got %s instead",
 			d.String())
 	}
 
@@ -567,12 +2130,12 @@ func TestBig_Sign(t *testing.T) {
 		x string
 		s int
 	}{
-		0: {"-Inf", 0},
+		0: {"-Inf", -1},
 		1: {"-1", -1},
 		2: {"-0", 0},
 		3: {"+0", 0},
 		4: {"+1", +1},
-		5: {"+Inf", 0},
+		5: {"+Inf", +1},
 		6: {"100", 1},
 		7: {"-100", -1},
 	} {
@@ -597,6 +2160,12 @@ func TestBig_SignBit(t *testing.T) {
 		1: {a: New(1, 0), b: false},
 		2: {a: x.Mul(x, x), b: false},
 		3: {a: new(Big).Neg(x), b: true},
+		4: {a: newbig(t, "-0"), b: true},
+		5: {a: newbig(t, "0"), b: false},
+		6: {a: newbig(t, "-Inf"), b: true},
+		7: {a: new(Big).SetInf(false), b: false},
+		8: {a: new(Big).SetFloat64(math.Copysign(0, -1)), b: true},
+		9: {a: new(Big).SetFloat64(math.Inf(-1)), b: true},
 	}
 	for i, v := range tests {
 		sb := v.a.SignBit()
@@ -606,6 +2175,80 @@ func TestBig_SignBit(t *testing.T) {
 	}
 }
 
+func TestBig_SignedSpecials(t *testing.T) {
+	for i, test := range [...]struct {
+		op   string
+		x, y string
+		res  string
+	}{
+		0:  {"Add", "-0", "-0", "-0"},
+		1:  {"Add", "-0", "0", "0"},
+		2:  {"Add", "1", "-1", "0"},
+		3:  {"Sub", "-0", "0", "-0"},
+		4:  {"Sub", "-0", "-0", "0"},
+		5:  {"Mul", "-0", "5", "-0"},
+		6:  {"Mul", "-Inf", "-2", "Inf"},
+		7:  {"Mul", "-1e+2000000000", "1e+2000000000", "-Inf"},
+		8:  {"Quo", "1", "-0", "-Inf"},
+		9:  {"Quo", "-0", "5", "-0"},
+		10: {"Quo", "-1", "Inf", "-0"},
+		11: {"Pow", "-0", "-3", "-Inf"},
+		12: {"Pow", "-0", "2", "0"},
+		13: {"Pow", "-Inf", "3", "-Inf"},
+		14: {"Neg", "Inf", "", "-Inf"},
+		15: {"Neg", "0", "", "-0"},
+		16: {"Abs", "-Inf", "", "Inf"},
+		17: {"Abs", "-0", "", "0"},
+		18: {"Sqrt", "-0", "", "-0"},
+		19: {"Set", "-Inf", "", "-Inf"},
+		20: {"Set", "-0.00", "", "-0"},
+	} {
+		x := newbig(t, test.x)
+		z := new(Big)
+		switch test.op {
+		case "Add":
+			z.Add(x, newbig(t, test.y))
+		case "Sub":
+			z.Sub(x, newbig(t, test.y))
+		case "Mul":
+			z.Mul(x, newbig(t, test.y))
+		case "Quo":
+			z.Quo(x, newbig(t, test.y))
+		case "Pow":
+			z.Pow(x, newbig(t, test.y))
+		case "Neg":
+			z.Neg(x)
+		case "Abs":
+			z.Abs(x)
+		case "Sqrt":
+			z.Sqrt(x)
+		case "Set":
+			z.Set(x)
+		}
+		if zs := z.String(); zs != test.res {
+			t.Errorf("#%d: %s(%s, %s) wanted %s, got %s", i, test.op, test.x, test.y, test.res, zs)
+		}
+	}
+}
+
+func TestBig_PlainString(t *testing.T) {
+	for i, test := range [...]struct {
+		x, r string
+	}{
+		0: {"1.5", "1.5"},
+		1: {"-1.5", "-1.5"},
+		2: {"-0.05", "-0.05"},
+		3: {"-1e-10", "-0.0000000001"},
+		4: {"-1.5e+3", "-1500"},
+		5: {"-12345678901234567890.5", "-12345678901234567890.5"},
+		6: {"-0", "-0"},
+	} {
+		if r := newbig(t, test.x).PlainString(); r != test.r {
+			t.Errorf("#%d: PlainString(%s) wanted %s, got %s", i, test.x, test.r, r)
+		}
+	}
+}
+
 func TestBig_String(t *testing.T) {
 	x := New(1<<63-1, 0)
 	tests := [...]struct {
@@ -632,23 +2275,29 @@ func TestSqrt(t *testing.T) {
 		v    string
 		sqrt string
 		prec int32
+		mode RoundingMode
 	}{
-		0:  {"25", "5", 0},
-		1:  {"100", "10", 0},
-		2:  {"250", "15.8113883008418966", 16},
-		3:  {"1000", "31.6227766016837933", 16},
-		4:  {"1000", "31.6227766016837933199889354", 25},
-		5:  {"1000", "31.6227766016837933199889354443271853371955513932521682685750485279259443863923822134424810837930029518", 100},
-		6:  {"4.9790119248836735e+00", "2.2313699659365484746324612", 25},
-		7:  {"7.7388724745781045e+00", "2.7818829009464263393517169", 25},
-		8:  {"9.6362937071984173e+00", "3.1042380236055380970754451", 25},
-		9:  {"2.9263772392439646e+00", "1.7106657298385224271646351", 25},
-		10: {"5.2290834314593066e+00", "2.2867189227054790347124042", 25},
-		11: {"2.7279399104360102e+00", "1.651647635071115948104434", 25},
-		12: {"1.8253080916808550e+00", "1.3510396336454586038718314", 25},
+		0:  {"25", "5", 0, ToNearestEven},
+		1:  {"100", "10", 0, ToNearestEven},
+		2:  {"250", "15.8113883008418967", 16, ToNearestEven},
+		3:  {"1000", "31.6227766016837933", 16, ToNearestEven},
+		4:  {"1000", "31.6227766016837933199889354", 25, ToNearestEven},
+		5:  {"1000", "31.6227766016837933199889354443271853371955513932521682685750485279259443863923822134424810837930029519", 100, ToNearestEven},
+		6:  {"4.9790119248836735e+00", "2.2313699659365484746324613", 25, ToNearestEven},
+		7:  {"7.7388724745781045e+00", "2.781882900946426339351717", 25, ToNearestEven},
+		8:  {"9.6362937071984173e+00", "3.1042380236055380970754451", 25, ToNearestEven},
+		9:  {"2.9263772392439646e+00", "1.7106657298385224271646352", 25, ToNearestEven},
+		10: {"5.2290834314593066e+00", "2.2867189227054790347124043", 25, ToNearestEven},
+		11: {"2.7279399104360102e+00", "1.6516476350711159481044341", 25, ToNearestEven},
+		12: {"1.8253080916808550e+00", "1.3510396336454586038718315", 25, ToNearestEven},
+		// n = k(k+1) is below the half, as √n < k + 1/2.
+		13: {"0.56", "0.7", 1, ToNearestEven},
+		14: {"0.42", "0.6", 1, ToNearestAway},
+		15: {"1524157876253619990E-20", "0.123456789", 10, ToNearestEven},
+		16: {"1524157876253619990E-20", "0.123456789", 10, ToNearestAway},
 	} {
 		var b Big
-		b.SetPrec(test.prec)
+		b.SetPrec(test.prec).SetMode(test.mode)
 		a, ok := b.SetString(test.v)
 		if !ok {
 			t.Fatal("wanted true, got false")
@@ -693,3 +2342,73 @@ func TestBig_Sub(t *testing.T) {
 		}
 	}
 }
+
+func TestBig_Traps(t *testing.T) {
+	for i, test := range [...]struct {
+		traps Condition
+		mode  RoundingMode
+		x, y  string
+		want  Condition // panic value, or 0 if Quo shouldn't panic
+	}{
+		0: {0, ToNearestEven, "1", "0", 0},
+		1: {DivisionByZero, ToNearestEven, "1", "0", DivisionByZero},
+		2: {DivisionByZero, ToNearestEven, "0", "0", 0},
+		3: {InvalidOperation, ToNearestEven, "0", "0", InvalidOperation},
+		4: {Inexact, ToNearestEven, "1", "3", Inexact},
+		5: {Inexact | Rounded, ToNearestEven, "1", "3", Inexact | Rounded},
+		6: {Overflow, ToNearestEven, "1", "3", 0},
+		7: {0, Unneeded, "1", "3", Inexact},
+		8: {0, Unneeded, "1", "4", 0},
+	} {
+		func() {
+			z := new(Big).SetTraps(test.traps).SetMode(test.mode)
+			defer func() {
+				r := recover()
+				if r == nil {
+					if test.want != 0 {
+						t.Errorf("#%d: Quo(%s, %s) didn't panic", i, test.x, test.y)
+					}
+					return
+				}
+				c, ok := r.(Condition)
+				if !ok || c != test.want {
+					t.Errorf("#%d: Quo(%s, %s) wanted panic(%s), got panic(%v)", i, test.x, test.y, test.want, r)
+				}
+				if z.Conditions()&c != c {
+					t.Errorf("#%d: trapped %s, but only %s were recorded", i, c, z.Conditions())
+				}
+			}()
+			z.Quo(newbig(t, test.x), newbig(t, test.y))
+		}()
+	}
+}
+
+func TestBig_Ulp(t *testing.T) {
+	for i, test := range [...]struct {
+		x   string
+		ctx Context
+		res string
+	}{
+		0:  {"1", Context{Prec: 3}, "0.01"},
+		1:  {"-1", Context{Prec: 3}, "0.01"},
+		2:  {"999", Context{Prec: 3}, "1"},
+		3:  {"1000", Context{Prec: 3}, "1e+1"},
+		4:  {"1.2345", Context{Prec: 16}, "1e-15"},
+		5:  {"1.2345", Context{Prec: -1}, "0.0001"},
+		6:  {"1.2e+10", Context{Prec: -1}, "1e+9"},
+		7:  {"0", Context{Prec: 16}, "1e-2147483647"},
+		8:  {"Inf", Context{Prec: 16}, "Inf"},
+		9:  {"0", Context64, "1e-398"},
+		10: {"1", Context64, "1e-15"},
+		11: {"1e-390", Context64, "1e-398"},
+		12: {"9.999999999999999e+384", Context64, "1e+369"},
+		13: {"1e+400", Context64, "Inf"},
+		14: {"0", Context32, "1e-101"},
+		15: {"1.5e-98", Context32, "1e-101"},
+	} {
+		z := new(Big).SetContext(test.ctx)
+		if zs := z.Ulp(newbig(t, test.x)).String(); zs != test.res {
+			t.Errorf("#%d: Ulp(%s) wanted %s, got %s", i, test.x, test.res, zs)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package decimal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format implements fmt.Formatter. It accepts the verbs and flags that
+// big.Float's Format does for decimal output:
+//
+//	'e'	-d.dddde±dd
+//	'E'	-d.ddddE±dd
+//	'f'	-ddddd.dddd
+//	'F'	same as 'f'
+//	'g'	like 'e' for large exponents, like 'f' otherwise
+//	'G'	like 'E' for large exponents, like 'f' otherwise
+//	'z'	the result of EngString
+//	'Z'	same as 'z', but with an 'E' exponent
+//	's', 'v'	the result of String
+//
+// For 'e', 'E' and 'f' the precision is the number of digits after the radix
+// and defaults to 6. For 'g' and 'G' it's the number of significant digits,
+// and without one x's digits are printed without trailing zeros. Digits
+// that don't fit are rounded using x's RoundingMode, or ToNearestEven if
+// it's Unneeded, without raising any conditions.
+//
+// The '+' and ' ' flags print a sign or a space before positive values, the
+// '-' flag pads with spaces on the right, the '0' flag pads finite values
+// with leading zeros, and the '#' flag always prints a radix point. ±Inf and
+// NaN values are formatted as in String.
+func (x *Big) Format(s fmt.State, verb rune) {
+	prec, hasPrec := s.Precision()
+	if !hasPrec {
+		prec = 6 // default precision for 'e' and 'f'
+	}
+
+	var buf []byte
+	switch verb {
+	case 'e', 'E', 'f':
+		// OK
+	case 'F':
+		verb = 'f'
+	case 'g', 'G':
+		if !hasPrec {
+			prec = -1
+		}
+	case 's', 'v':
+		buf = []byte(x.String())
+	case 'z':
+		buf = []byte(x.toEngString(lower))
+	case 'Z':
+		buf = []byte(x.toEngString(upper))
+	default:
+		fmt.Fprintf(s, "%%!%c(*decimal.Big=%s)", verb, x.String())
+		return
+	}
+	if buf == nil {
+		if x == nil || x.form != finite && x.form != zero {
+			buf = []byte(x.String())
+		} else {
+			buf = x.append(buf, byte(verb), prec, s.Flag('#'))
+		}
+	}
+
+	var sign string
+	switch {
+	case buf[0] == '-':
+		sign = "-"
+		buf = buf[1:]
+	case x == nil || x.IsNaN():
+		// No sign.
+	case s.Flag('+'):
+		sign = "+"
+	case s.Flag(' '):
+		sign = " "
+	}
+
+	var padding int
+	if width, hasWidth := s.Width(); hasWidth && width > len(sign)+len(buf) {
+		padding = width - len(sign) - len(buf)
+	}
+
+	switch {
+	case s.Flag('0') && x != nil && (x.form == finite || x.form == zero):
+		// Zero padding on the left.
+		writeN(s, sign, 1)
+		writeN(s, "0", padding)
+		s.Write(buf)
+	case s.Flag('-'):
+		// Padding on the right.
+		writeN(s, sign, 1)
+		s.Write(buf)
+		writeN(s, " ", padding)
+	default:
+		// Padding on the left.
+		writeN(s, " ", padding)
+		writeN(s, sign, 1)
+		s.Write(buf)
+	}
+}
+
+// writeN writes s to w n times.
+func writeN(w fmt.State, s string, n int) {
+	if s != "" {
+		b := []byte(s)
+		for ; n > 0; n-- {
+			w.Write(b)
+		}
+	}
+}
+
+// append appends finite x to buf, formatted as the Format verb fmt with the
+// given precision, and returns the extended buffer. A negative precision
+// means all of x's digits. If sharp is true the radix is always written.
+func (x *Big) append(buf []byte, fmt byte, prec int, sharp bool) []byte {
+	shortest := prec < 0
+
+	// Round a copy of x to the digits that will be written.
+	var t Big
+	t.Set(x)
+	t.ctx.Traps = 0
+	if t.ctx.Mode == Unneeded {
+		t.ctx.Mode = ToNearestEven
+	}
+	if !shortest && t.form == finite {
+		switch fmt {
+		case 'e', 'E':
+			t.roundToPrec(int32(prec + 1))
+		case 'f':
+			if int64(t.scale) > int64(prec) {
+				t.shrink(int64(t.scale)-int64(prec), t.ctx.Mode)
+			}
+		case 'g', 'G':
+			if prec == 0 {
+				prec = 1
+			}
+			t.roundToPrec(int32(prec))
+		}
+	}
+	d, exp := t.digits()
+
+	if t.SignBit() {
+		buf = append(buf, '-')
+	}
+
+	switch fmt {
+	case 'e', 'E':
+		if shortest {
+			prec = len(d) - 1
+		}
+		return fmtE(buf, fmt, prec, sharp, d, exp)
+	case 'f':
+		return fmtF(buf, prec, sharp, d, exp)
+	}
+
+	// %g and %G
+	if !sharp || shortest {
+		for len(d) > 0 && d[len(d)-1] == '0' {
+			d = d[:len(d)-1]
+		}
+	}
+	if sharp {
+		// Write at least prec significant digits, or 6 if the precision is
+		// the shortest possible, as fmt does for floats. A zero has one.
+		n := prec
+		if shortest {
+			n = 6
+		}
+		if len(d) == 0 {
+			d, exp = "0", 1
+		}
+		if n > len(d) {
+			d += strings.Repeat("0", n-len(d))
+		}
+	}
+	if shortest {
+		prec = len(d)
+	}
+
+	// %e is used if the exponent from the conversion is less than -4 or
+	// greater than or equal to the precision. If the precision is the
+	// shortest possible, 6 is used for this decision.
+	eprec := int64(prec)
+	if eprec > int64(len(d)) && int64(len(d)) >= exp {
+		eprec = int64(len(d))
+	}
+	if shortest {
+		eprec = 6
+	}
+	if e := exp - 1; e < -4 || e >= eprec {
+		if prec > len(d) {
+			prec = len(d)
+		}
+		return fmtE(buf, fmt+'e'-'g', prec-1, sharp, d, exp)
+	}
+	if int64(prec) > exp {
+		prec = len(d)
+	}
+	p := int64(prec) - exp
+	if p < 0 {
+		p = 0
+	}
+	return fmtF(buf, int(p), sharp, d, exp)
+}
+
+// digits returns the decimal digits of |x|'s mantissa and the exponent exp
+// such that |x| == 0.d × 10**exp. d is empty if x is zero. x must be finite.
+func (x *Big) digits() (d string, exp int64) {
+	if x.isZero() {
+		return "", 0
+	}
+	if x.isCompact() {
+		d = strconv.FormatUint(uabs(x.compact), 10)
+	} else {
+		d = x.mantissa.String()
+		if d[0] == '-' {
+			d = d[1:]
+		}
+	}
+	return d, int64(len(d)) - int64(x.scale)
+}
+
+// fmtE appends d × 10**exp formatted as %e with prec digits after the radix
+// to buf.
+func fmtE(buf []byte, fmt byte, prec int, sharp bool, d string, exp int64) []byte {
+	ch := byte('0')
+	if len(d) > 0 {
+		ch = d[0]
+	}
+	buf = append(buf, ch)
+
+	if prec > 0 || sharp {
+		buf = append(buf, '.')
+		i := 1
+		m := len(d)
+		if prec+1 < m {
+			m = prec + 1
+		}
+		if i < m {
+			buf = append(buf, d[i:m]...)
+			i = m
+		}
+		for ; i <= prec; i++ {
+			buf = append(buf, '0')
+		}
+	}
+
+	buf = append(buf, fmt)
+	var e int64
+	if len(d) > 0 {
+		e = exp - 1
+	}
+	if e < 0 {
+		ch = '-'
+		e = -e
+	} else {
+		ch = '+'
+	}
+	buf = append(buf, ch)
+	if e < 10 {
+		buf = append(buf, '0')
+	}
+	return strconv.AppendInt(buf, e, 10)
+}
+
+// fmtF appends d × 10**exp formatted as %f with prec digits after the radix
+// to buf.
+func fmtF(buf []byte, prec int, sharp bool, d string, exp int64) []byte {
+	if exp > 0 {
+		m := int64(len(d))
+		if m > exp {
+			m = exp
+		}
+		buf = append(buf, d[:m]...)
+		for ; m < exp; m++ {
+			buf = append(buf, '0')
+		}
+	} else {
+		buf = append(buf, '0')
+	}
+
+	if prec > 0 || sharp {
+		buf = append(buf, '.')
+		for i := int64(1); i <= int64(prec); i++ {
+			ch := byte('0')
+			if j := exp + i - 1; j >= 0 && j < int64(len(d)) {
+				ch = d[j]
+			}
+			buf = append(buf, ch)
+		}
+	}
+	return buf
+}
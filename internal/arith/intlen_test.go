@@ -1,6 +1,7 @@
 package arith
 
 import (
+	"math"
 	"math/big"
 	"testing"
 )
@@ -30,6 +31,7 @@ func TestLength(t *testing.T) {
 		{i: 10000000000000000, l: 17},
 		{i: 100000000000000000, l: 18},
 		{i: 1000000000000000000, l: 19},
+		{i: math.MinInt64, l: 19},
 	}
 	for i, v := range tests {
 		if l := Length(v.i); l != v.l {
@@ -93,6 +93,7 @@ func Thresh(t int32) int64 {
 }
 
 func init() {
+	pow10tab[0] = 1
 	pow10tab[1] = 10
 	for i := 2; i < Tab64Len; i++ {
 		m := i / 2
@@ -106,7 +107,7 @@ func init() {
 	}
 
 	bigPow10Tab.x = make([]big.Int, Tab64Len)
-	for i := int64(1); i < Tab64Len; i++ {
+	for i := int64(0); i < Tab64Len; i++ {
 		p, _ := Ten64(i)
 		bigPow10Tab.x[i] = *big.NewInt(p)
 	}
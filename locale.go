@@ -0,0 +1,170 @@
+package decimal
+
+import (
+	"bytes"
+	"strings"
+)
+
+// MinusStyle is how a Locale marks negative numbers.
+type MinusStyle uint8
+
+// The following minus styles are supported.
+const (
+	MinusLeading  MinusStyle = iota // -1.5
+	MinusTrailing                   // 1.5-
+	MinusParens                     // (1.5)
+)
+
+// Locale describes how numbers are written in a particular locale. The zero
+// value writes numbers like PlainString.
+type Locale struct {
+	// Decimal is the decimal mark. If it's empty "." is used.
+	Decimal string
+
+	// Group separates groups of digits in the integer part.
+	Group string
+
+	// Grouping lists the sizes of the groups of digits in the integer part,
+	// starting at the decimal mark. The last size is repeated for the rest
+	// of the digits, and a size less than one ends the grouping. Digits are
+	// only grouped if Group and Grouping are both non-empty.
+	Grouping []int
+
+	// Minus is the minus sign. If it's empty "-" is used. It's ignored if
+	// MinusStyle is MinusParens.
+	Minus string
+
+	// MinusStyle is where the minus sign of a negative number is written.
+	MinusStyle MinusStyle
+
+	// Digits are the digits 0 through 9. If it's the zero value the ASCII
+	// digits are used.
+	Digits [10]rune
+}
+
+// Some common Locales.
+var (
+	// LocaleUS writes 1,234,567.89.
+	LocaleUS = Locale{Decimal: ".", Group: ",", Grouping: []int{3}}
+
+	// LocaleDE writes 1.234.567,89.
+	LocaleDE = Locale{Decimal: ",", Group: ".", Grouping: []int{3}}
+
+	// LocaleFR writes 1 234 567,89, with narrow no-break spaces between the
+	// groups and a Unicode minus sign.
+	LocaleFR = Locale{Decimal: ",", Group: "\u202f", Grouping: []int{3}, Minus: "\u2212"}
+
+	// LocaleCH writes 1'234'567.89.
+	LocaleCH = Locale{Decimal: ".", Group: "'", Grouping: []int{3}}
+
+	// LocaleIN writes 12,34,567.89.
+	LocaleIN = Locale{Decimal: ".", Group: ",", Grouping: []int{3, 2}}
+)
+
+// Format returns x formatted using l with prec digits after the decimal
+// mark. Digits that don't fit are rounded as in Format's 'f' verb. If prec
+// is negative all of x's digits are written, without trailing zeros after
+// the decimal mark, as in PlainString. ±Inf is written as Inf with l's minus
+// sign and NaN as in String.
+func (l Locale) Format(x *Big, prec int) string {
+	var s string
+	switch {
+	case x == nil || x.IsNaN():
+		return x.String()
+	case x.IsInf():
+		s = x.String()
+	case prec < 0:
+		s = x.PlainString()
+	default:
+		s = string(x.append(nil, 'f', prec, false))
+	}
+
+	neg := s[0] == '-'
+	if neg {
+		s = s[1:]
+	}
+	ip, fp := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		ip, fp = s[:i], s[i+1:]
+	}
+
+	var b bytes.Buffer
+	if neg {
+		switch l.MinusStyle {
+		case MinusLeading:
+			b.WriteString(l.minus())
+		case MinusParens:
+			b.WriteByte('(')
+		}
+	}
+	l.writeGrouped(&b, ip)
+	if fp != "" {
+		if l.Decimal != "" {
+			b.WriteString(l.Decimal)
+		} else {
+			b.WriteByte('.')
+		}
+		l.writeDigits(&b, fp)
+	}
+	if neg {
+		switch l.MinusStyle {
+		case MinusTrailing:
+			b.WriteString(l.minus())
+		case MinusParens:
+			b.WriteByte(')')
+		}
+	}
+	return b.String()
+}
+
+func (l Locale) minus() string {
+	if l.Minus != "" {
+		return l.Minus
+	}
+	return "-"
+}
+
+// writeGrouped writes the integer part s to b, grouping its digits.
+func (l Locale) writeGrouped(b *bytes.Buffer, s string) {
+	if l.Group == "" || len(l.Grouping) == 0 || s == "Inf" {
+		l.writeDigits(b, s)
+		return
+	}
+
+	// sizes holds the sizes of the groups after the first, right to left.
+	var sizes []int
+	n := len(s)
+	for i := 0; ; {
+		size := l.Grouping[i]
+		if size < 1 || size >= n {
+			break
+		}
+		n -= size
+		sizes = append(sizes, size)
+		if i < len(l.Grouping)-1 {
+			i++
+		}
+	}
+
+	l.writeDigits(b, s[:n])
+	for i := len(sizes) - 1; i >= 0; i-- {
+		b.WriteString(l.Group)
+		l.writeDigits(b, s[n:n+sizes[i]])
+		n += sizes[i]
+	}
+}
+
+// writeDigits writes the ASCII digits in s to b using l's digits.
+func (l Locale) writeDigits(b *bytes.Buffer, s string) {
+	if l.Digits == [10]rune{} {
+		b.WriteString(s)
+		return
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= '0' && c <= '9' {
+			b.WriteRune(l.Digits[c-'0'])
+		} else {
+			b.WriteByte(c)
+		}
+	}
+}
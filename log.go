@@ -1,195 +1,462 @@
 package decimal
 
 import (
-	"fmt"
-	"math"
 	"math/big"
+
+	"github.com/EricLagergren/decimal/internal/arith"
+	"github.com/EricLagergren/decimal/internal/arith/checked"
+	"github.com/EricLagergren/decimal/internal/arith/pow"
+	"github.com/EricLagergren/decimal/internal/c"
 )
 
-// logNewton sets z to the natural logarithm of x
-// using the Newtonian method and returns z.
-func (z *Big) logNewton(x *Big) *Big {
-	sp := z.ctx.prec() + 1
-	x0 := new(Big).Set(x)
-	tol := New(5, sp)
-	var term, etx Big
-	term.ctx.precision = sp
-	etx.ctx.precision = sp
-	for {
-		etx.exp(x0)
-		term.Sub(&etx, x)
-		term.Quo(&term, &etx)
-		x0.Sub(x0, &term)
-		if term.Cmp(tol) < 0 {
-			break
+// exp sets z to e ** x and returns z. The result is computed using z's
+// precision plus a few guard digits, but is not rounded to z's precision.
+// x must be finite and |x| must be less than 1e10.
+func (z *Big) exp(x *Big) *Big {
+	wp := z.ctx.prec()
+
+	// Reduce x such that x = k * ln(10) + r, |r| < ln(10). Then
+	// e ** x = e ** r * 10 ** k, and the multiplication by 10 ** k
+	// is a simple scale adjustment.
+	l10 := ln10(wp + 12) // |k| < 1e10, so 12 extra digits is plenty.
+	var k Big
+	k.ctx.Prec = 20
+	k.Quo(x, l10)
+	ki := k.Int64()
+
+	var r Big
+	r.Sub(x, k.Mul(New(ki, 0), l10))
+	r.ctx = Context{Prec: wp}
+	r.roundToPrec(wp)
+
+	// Further reduce r by 2 ** expShift so the Taylor series converges
+	// quickly, then square the sum expShift times.
+	const expShift = 8
+	r.Quo(&r, New(1<<expShift, 0))
+	z.taylor(&r)
+	for i := 0; i < expShift; i++ {
+		z.Mul(z, z).roundToPrec(wp)
+	}
+
+	scale, ok := checked.Int32(int64(z.scale) - ki)
+	if !ok {
+		if ki < 0 {
+			return z.underflow(false)
 		}
+		return z.overflow(false)
 	}
-	*z = *x0
+	z.scale = scale
 	return z
 }
 
-// exp sets z to e ** x and returns z.
-func (z *Big) exp(x *Big) *Big {
-	if x.ez() {
-		z.ctx.precision = z.ctx.prec()
-		return z.SetMantScale(1, 0)
-	}
+// taylor sets z to e ** x using the Taylor series and returns z. x should
+// be small, otherwise the series converges slowly.
+func (z *Big) taylor(x *Big) *Big {
+	// Taylor series: Σ x^n/n!
 
-	if x.ltz() {
-		x0 := new(Big).Set(x)
-		// 1 / (e ** -x)
-		return z.Quo(New(1, 0), x0.exp(x0.Neg(x0)))
+	wp := z.ctx.prec()
+	var sum, term Big
+	sum.ctx = Context{Prec: wp}
+	term.ctx = sum.ctx
+	sum.SetMantScale(1, 0)
+	term.SetMantScale(1, 0)
+	for i := int64(1); ; i++ {
+		term.Mul(&term, x).roundToPrec(wp)
+		term.Quo(&term, New(i, 0))
+		if term.ez() || term.adjusted() < -int64(wp)-1 {
+			break
+		}
+		sum.Add(&sum, &term).roundToPrec(wp)
 	}
+	ctx := z.ctx
+	z.Set(&sum)
+	z.ctx = ctx
+	return z
+}
+
+// log sets z to the natural logarithm of x and returns z. The result is
+// computed using z's precision plus a few guard digits, but is not rounded to
+// z's precision. x must be finite and greater than zero.
+func (z *Big) log(x *Big) *Big {
+	wp := z.ctx.prec()
 
-	dec, frac := z.Modf(x)
-	if dec.ez() {
-		return z.taylor(x)
+	// Reduce x such that x = f * 2 ** a * 10 ** e, 1/sqrt(2) <= f < sqrt(2).
+	// Then ln(x) = ln(f) + a * ln(2) + e * ln(10). Choosing e and a such
+	// that they're both zero when x is close to 1 keeps the sum from
+	// losing precision to cancellation.
+	var f Big
+	f.Set(x)
+	f.ctx = Context{Prec: wp + 2}
+	e := x.adjusted()
+	f.scale = int32(int64(f.scale) + e) // f in [1, 10)
+	if f.Cmp(sqrt10) >= 0 {
+		f.scale++
+		e++
+	}
+	var a int64
+	for ; f.Cmp(sqrt2) >= 0; a++ {
+		// f / 2 == f * 5 / 10, which is exact.
+		f.Mul(&f, five).scale++
+	}
+	for ; f.Cmp(invSqrt2) < 0; a-- {
+		f.Mul(&f, two)
 	}
 
-	o := New(1, 0)
-	o.ctx.precision = z.ctx.prec()
-	o.Add(z, frac.Quo(frac, dec))
-	o.taylor(o)
+	// ln(f) = 2 * atanh((f - 1) / (f + 1))
+	var u, t Big
+	u.ctx = f.ctx
+	t.ctx = f.ctx
+	u.Quo(u.Sub(&f, one), t.Add(&f, one))
+	z.atanh(&u)
+	z.Mul(z, two)
 
-	res := New(1, 0)
-	for dec.Cmp(max64) >= 0 {
-		res.Mul(res, new(Big).powInt(o, math.MaxInt64))
-		dec.Sub(dec, max64)
+	if a != 0 {
+		z.Add(z, t.Mul(New(a, 0), ln2(wp+2)))
+	}
+	if e != 0 {
+		// |e| < 1e10, so 12 extra digits is plenty.
+		z.Add(z, t.Mul(New(e, 0), ln10(wp+12)))
 	}
-	return z.Mul(res, o.powInt(o, dec.Int64()))
+	return z.roundToPrec(wp)
 }
 
-// taylor sets z to e ** x using the Taylor series and returns z.
-func (z *Big) taylor(x *Big) *Big {
-	// Taylor series: Σ x^n/n!
+// atanh sets z to the inverse hyperbolic tangent of x and returns z. The
+// result is computed using z's precision. |x| should be small, otherwise the
+// series converges slowly.
+func (z *Big) atanh(x *Big) *Big {
+	// atanh(x) = Σ x^(2k+1)/(2k+1)
 
-	z.Add(z.Set(x), one) // 1 + x, our sum
-
-	var (
-		fac            = New(1, 0) // factorial
-		xp, prev, term Big         // x power, previous sum, and current term
-		zp             = z.ctx.prec()
-	)
-	xp.Set(x) // Stack allocation..?
-	for i := int64(2); ; i++ {
-		// x ^ n / n!
-		term.Quo(xp.Mul(&xp, x), fac.Mul(fac, New(i, 0)))
-		z.Round(zp)
-		prev.Set(z)     // save current sum
-		z.Add(z, &term) // sum += current term
-		z.Round(zp)
-
-		fmt.Println(z, &prev, zp)
-
-		// Convergence test.
-		// http://math.stackexchange.com/a/281971/153292
-		if z.Cmp(&prev) == 0 {
+	prec := z.ctx.prec()
+	var sum, pw, x2, term Big
+	sum.Set(x)
+	pw.Set(x)
+	sum.ctx = Context{Prec: prec}
+	pw.ctx = sum.ctx
+	term.ctx = sum.ctx
+	x2.Mul(x, x).roundToPrec(prec)
+	for k := int64(1); ; k++ {
+		pw.Mul(&pw, &x2).roundToPrec(prec)
+		term.Quo(&pw, New(2*k+1, 0))
+		if term.ez() || term.adjusted() < sum.adjusted()-int64(prec)-1 {
 			break
 		}
+		sum.Add(&sum, &term).roundToPrec(prec)
 	}
+	ctx := z.ctx
+	z.Set(&sum)
+	z.ctx = ctx
 	return z
 }
 
-var _ = fmt.Println
+// log10 sets z to the base-10 logarithm of x and returns z. See log for
+// more information.
+func (z *Big) log10(x *Big) *Big {
+	wp := z.ctx.prec()
+	z.log(x)
+	return z.Quo(z, ln10(wp+2))
+}
+
+// log2 sets z to the base-2 logarithm of x and returns z. See log for more
+// information.
+func (z *Big) log2(x *Big) *Big {
+	wp := z.ctx.prec()
+	z.log(x)
+	return z.Quo(z, ln2(wp+2))
+}
+
+// ln10 returns ln(10) with at least prec digits of precision.
+func ln10(prec int32) *Big {
+	if int(prec)+1 < len(c.Ln10) {
+		x, _ := new(Big).SetString(c.Ln10[:prec+1])
+		return x
+	}
+	// ln(10) = 3 * ln(2) + ln(1.25)
+	//        = 6 * atanh(1/3) + 2 * atanh(1/9)
+	wp := prec + 5
+	x := new(Big).SetPrec(wp).atanh(new(Big).SetPrec(wp).Quo(one, New(3, 0)))
+	x.Mul(x, New(6, 0))
+	y := new(Big).SetPrec(wp).atanh(new(Big).SetPrec(wp).Quo(one, New(9, 0)))
+	y.Mul(y, two)
+	return x.Add(x, y).roundToPrec(wp)
+}
+
+// ln2 returns ln(2) with at least prec digits of precision.
+func ln2(prec int32) *Big {
+	if int(prec)+2 < len(c.Ln2) {
+		x, _ := new(Big).SetString(c.Ln2[:prec+2])
+		return x
+	}
+	// ln(2) = 2 * atanh(1/3)
+	wp := prec + 5
+	x := new(Big).SetPrec(wp).atanh(new(Big).SetPrec(wp).Quo(one, New(3, 0)))
+	return x.Mul(x, two).roundToPrec(wp)
+}
+
+// isPow10 returns k and true if x == 10 ** k.
+func isPow10(x *Big) (k int64, ok bool) {
+	if !x.gtz() {
+		return 0, false
+	}
+	if x.isCompact() {
+		p, _ := pow.Ten64(int64(x.Prec() - 1))
+		return x.adjusted(), x.compact == p
+	}
+	p := pow.BigTen(int64(x.Prec() - 1))
+	return x.adjusted(), x.mantissa.Cmp(&p) == 0
+}
 
-// pow sets d to x ** y and returns z.
-func (z *Big) pow(x *Big, y *big.Int) *Big {
+// isPow2 returns k and true if x == 2 ** k.
+func isPow2(x *Big) (k int64, ok bool) {
+	if !x.gtz() {
+		return 0, false
+	}
+	var m, r big.Int
+	if x.isCompact() {
+		m.SetInt64(x.compact)
+	} else {
+		m.Set(&x.mantissa)
+	}
+	// Remove trailing zeros so that x == m * 10 ** -s and m % 10 != 0.
+	s := int64(x.scale)
+	for {
+		var q big.Int
+		q.QuoRem(&m, c.TenInt, &r)
+		if r.Sign() != 0 {
+			break
+		}
+		m.Set(&q)
+		s--
+	}
 	switch {
-	case y.Sign() < 0, (x.ez() || y.Sign() == 0):
-		return z.SetMantScale(1, 0)
-	case y.Cmp(oneInt) == 0:
-		return z.Set(x)
-	case x.ez():
-		if x.isOdd() {
-			return z.Set(x)
+	case s == 0:
+		// x == m, an integer.
+		n := m.BitLen() - 1
+		if r.Lsh(oneInt, uint(n)).Cmp(&m) == 0 {
+			return int64(n), true
+		}
+	case s > 0:
+		// x == m / (2 ** s * 5 ** s), which is a power of two only if
+		// m == 5 ** s.
+		if s <= int64(m.BitLen()) && r.Exp(big.NewInt(5), big.NewInt(s), nil).Cmp(&m) == 0 {
+			return -s, true
 		}
-		z.form = zero
-		return z
 	}
+	return 0, false
+}
 
-	x0 := new(Big).Set(x)
-	y0 := new(big.Int).Set(y)
-	ret := New(1, 0)
-	var odd big.Int
-	for y0.Sign() > 0 {
-		if odd.And(y0, oneInt).Sign() != 0 {
-			ret.Mul(ret, x0)
+// ziv sets z to f(x), correctly rounded to z's precision using z's
+// RoundingMode and brought into its Context's exponent range, and returns z.
+//
+// f must compute its result using the precision of its receiver and the
+// result must be accurate to within a few units in the last place. ziv
+// retries with increasing precision until the rounding of the result can be
+// determined. Since f(x) could be exact (which is indistinguishable from a
+// result that lies very close to a rounding boundary) the number of retries
+// is bounded.
+func (z *Big) ziv(x *Big, f func(z, x *Big) *Big) *Big {
+	const maxRetries = 4
+
+	zp := z.zivPrec()
+	wp := zp + 12
+	var t Big
+	for i := 0; ; i++ {
+		t.ctx = Context{Prec: wp}
+		f(&t, x)
+		if t.form != finite || roundable(&t, zp) {
+			break
+		}
+		if i == maxRetries {
+			// Likely an exact result: remove the noise before rounding.
+			t.roundToPrec(wp - zivErrDigits)
+			break
 		}
-		y0.Rsh(y0, 1)
-		x0.Mul(x0, x0)
+		wp *= 2
+	}
+	// t's Context has no exponent limits, so it only overflows (or
+	// underflows) if its exponent doesn't fit in an int32.
+	switch {
+	case t.ctx.Conditions&Overflow != 0:
+		return z.overflow(t.SignBit())
+	case t.ctx.Conditions&Underflow != 0:
+		return z.underflow(t.SignBit())
 	}
-	*z = *ret
-	return ret
+	ctx := z.ctx
+	z.Set(&t)
+	z.ctx = ctx
+	if z.ctx.prec() == 0 {
+		return z.roundToPrec(zp)
+	}
+	return z.fix()
 }
 
-// integralRoot sets d to the integral root of x and returns z.
-func (z *Big) integralRoot(x *Big, index int64) *Big {
-	if x.ltz() {
-		panic(ErrNaN{"integralRoot: x < 0"})
+// zivPrec returns the precision ziv rounds z to: z's precision or, if it's
+// unlimited, DefaultPrec. The results ziv computes are rarely exact, so they
+// can't be computed to an unlimited precision.
+func (z *Big) zivPrec() int32 {
+	if zp := z.ctx.prec(); zp != 0 {
+		return zp
 	}
+	return DefaultPrec
+}
 
-	sp := z.ctx.prec() + 1
-	i := New(index, 0)
-	im1 := New(index-1, 0)
-	tol := New(5, sp)
-	x0 := new(Big).Set(x)
+// zivErrDigits is the maximum number of digits in the error of a
+// computation passed to ziv.
+const zivErrDigits = 4
 
-	x.Quo(x, i)
+// roundable returns true if x, the result of a computation that is in error
+// by less than 10 ** zivErrDigits units in the last place, can be correctly
+// rounded to prec digits.
+func roundable(x *Big, prec int32) bool {
+	g := int64(x.Prec()) - int64(prec)
+	if g <= zivErrDigits {
+		return false
+	}
+	var m big.Int
+	if x.isCompact() {
+		m.SetInt64(x.compact)
+	} else {
+		m.Set(&x.mantissa)
+	}
+	m.Abs(&m)
 
-	var prev *Big
-	var xx, xtoi1, xtoi, num, denom Big
-	for {
-		xtoi1.powInt(x, index-1)
-		xtoi.Mul(x, &xtoi1)
-		num.Add(x, new(Big).Mul(im1, &xtoi))
-		denom.Mul(i, &xtoi1)
-		prev = x0
-		x0.Quo(&num, &denom)
-		if xx.Sub(x0, prev).Abs(&xx).Cmp(tol) <= 0 {
-			break
-		}
+	// The discarded digits must not be within the error of 0, 1, or 0.5
+	// units in the last place of the rounded result.
+	p := pow.BigTen(g)
+	tol := pow.BigTen(zivErrDigits)
+	var r, d big.Int
+	r.Rem(&m, &p)
+	if r.Cmp(&tol) <= 0 || d.Sub(&p, &r).Cmp(&tol) <= 0 {
+		return false
 	}
-	*z = *x0
-	return z
+	d.Quo(&p, twoInt)
+	return d.Sub(&r, &d).Abs(&d).Cmp(&tol) > 0
 }
 
-// pow sets d to x ** y and returns z.
-func (z *Big) powInt(x *Big, y int64) *Big {
+// pow sets z to x ** y and returns z. The result is computed using z's
+// precision plus a few guard digits, but is not rounded to z's precision.
+// x must be finite and nonzero, and y must be finite. If x < 0, y must be an
+// integer.
+func (z *Big) pow(x, y *Big) *Big {
+	wp := z.ctx.prec()
+
+	// An error of 10 ** -p in y * ln(x) is a relative error of about
+	// 10 ** -p in x ** y, so y * ln(x) needs as many digits to the right of
+	// the radix as x ** y needs in total. |y * ln(x)| < 1e10 or else x ** y
+	// overflows (or underflows), so 12 extra digits is plenty.
+	var t Big
+	t.ctx = Context{Prec: wp + 12}
+	t.log(new(Big).Abs(x))
+	t.Mul(&t, y)
+
 	switch {
-	case y < 0, (x.ez() || y == 0):
-		return z.SetMantScale(1, 0)
-	case y == 1:
-		return z.Set(x)
-	case x.ez():
-		if x.isOdd() {
-			return z.Set(x)
+	case t.ez():
+		z.SetMantScale(1, 0)
+	case t.adjusted() >= 10:
+		if t.ltz() {
+			z.underflow(false)
+		} else {
+			z.overflow(false)
 		}
-		z.form = zero
-		return z
+	case t.adjusted() < -int64(wp):
+		// e ** t == 1 + t + t ** 2 / 2 + ..., and t ** 2 is well below the
+		// last digit of 1 + t.
+		ctx := z.ctx
+		z.Add(one, &t)
+		z.ctx = ctx
+	default:
+		z.exp(&t)
 	}
-
-	x0 := new(Big).Set(x)
-	ret := New(1, 0)
-	for y > 0 {
-		if y&1 == 1 {
-			ret.Mul(ret, x0)
-		}
-		x0.Mul(x0, x0)
-		y >>= 1
+	if x.ltz() && y.isOdd() {
+		z.Neg(z)
 	}
-	return z.Set(ret)
+	return z
 }
 
-// isOdd returns true if d is odd.
-func (x *Big) isOdd() (odd bool) {
-	if !x.IsInt() {
+// powExactDigits is the largest number of digits powInt will compute exactly.
+const powExactDigits = 10000
+
+// powInt sets z to x ** n, correctly rounded to z's precision using z's
+// RoundingMode and brought into its Context's exponent range, and returns
+// true. If n < 0 and z's precision is unlimited, z is rounded to DefaultPrec
+// digits. x must be finite and nonzero. The power is computed exactly before
+// it's rounded, so if the exact result would be too large powInt returns
+// false and leaves z unchanged.
+func (z *Big) powInt(x *Big, n int64) bool {
+	an := arith.Abs(n)
+	if an < 0 {
+		// n == math.MinInt64
+		return false
+	}
+	if d, ok := checked.Mul(int64(x.Prec()), an); !ok || d > powExactDigits {
 		return false
 	}
-	dec, frac := new(Big).Modf(x)
-	if dec.isCompact() {
-		odd = x.compact&1 != 0
+	if s, ok := checked.Mul(int64(x.scale), an); !ok || int64(int32(s)) != s {
+		return false
+	}
+
+	p := new(Big).Set(x)
+	r := New(1, 0)
+	for an > 0 {
+		if an&1 != 0 {
+			r.Mul(r, p)
+		}
+		an >>= 1
+		if an > 0 {
+			p.Mul(p, p)
+		}
+	}
+
+	ctx := z.ctx
+	zp := z.zivPrec()
+	if n < 0 {
+		z.ctx.Prec = zp
+		z.inv(r)
+	} else {
+		z.Set(r)
+	}
+	z.ctx = ctx
+	if n < 0 && z.ctx.prec() == 0 {
+		// 1 / r is rarely exact, so it's rounded like the results of ziv.
+		z.roundToPrec(zp)
+	} else {
+		z.fix()
+	}
+	return true
+}
+
+// inv sets z to 1 / x, truncated to at least z's precision plus one digit,
+// and returns z. The last digit is nonzero if the division is inexact, so z
+// rounds correctly to z's precision. x must be finite and nonzero.
+func (z *Big) inv(x *Big) *Big {
+	// x == m * 10 ** -s, so 1 / x == 10 ** k / m * 10 ** (s - k).
+	var m big.Int
+	if x.isCompact() {
+		m.SetInt64(x.compact)
 	} else {
-		odd = new(big.Int).And(&x.mantissa, oneInt).Cmp(oneInt) == 0
+		m.Set(&x.mantissa)
 	}
-	return frac.ez() && odd
+	k := int64(x.Prec()) + int64(z.ctx.prec()) + 1
+	scale, ok := checked.Int32(k - int64(x.scale) + 1)
+	if !ok {
+		neg := x.SignBit()
+		if x.scale < 0 {
+			return z.underflow(neg)
+		}
+		return z.overflow(neg)
+	}
+
+	var q, r big.Int
+	p := pow.BigTen(k)
+	q.QuoRem(&p, &m, &r)
+	q.Mul(&q, c.TenInt)
+	if r.Sign() != 0 {
+		// Sticky digit.
+		q.Add(&q, big.NewInt(int64(q.Sign())))
+	}
+	return z.SetBigMantScale(&q, scale)
+}
+
+// isOdd returns true if x is an odd integer.
+func (x *Big) isOdd() bool {
+	return x.form == finite && x.IsInt() && x.Int().Bit(0) != 0
 }
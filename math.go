@@ -1,8 +1,11 @@
 package decimal
 
 import (
+	"math"
 	"math/big"
 
+	"github.com/EricLagergren/decimal/internal/arith"
+	"github.com/EricLagergren/decimal/internal/arith/checked"
 	"github.com/EricLagergren/decimal/internal/arith/pow"
 	"github.com/EricLagergren/decimal/internal/c"
 )
@@ -14,32 +17,37 @@ func (z *Big) Modf(x *Big) (int *Big, frac *Big) {
 	int = z
 	frac = new(Big)
 
+	if z.checkNaNs(x) {
+		frac.Set(z)
+		return z, frac
+	}
+
 	if x.form == zero {
-		z.form = zero
-		frac.form = zero
+		z.setZero(x.SignBit())
+		frac.setZero(x.SignBit())
 		return z, frac
 	}
 
 	if x.form == inf {
-		z.form = inf
-		frac.form = inf
+		z.SetInf(x.SignBit())
+		frac.SetInf(x.SignBit())
 		return z, frac
 	}
 
-	z.ctx = x.ctx
 	z.form = finite
 
 	// Needs proper scale.
 	// Set frac before z in case z aliases x.
 	frac.scale = x.scale
-	frac.ctx = x.ctx
+	frac.ctx = z.ctx
 	frac.form = finite
 
 	if x.IsInt() {
-		if x.isCompact() {
-			z.compact = x.compact
+		if i := x.Int(); i.IsInt64() && i.Int64() != c.Inflated {
+			z.compact = i.Int64()
 		} else {
-			z.mantissa.Set(&x.mantissa)
+			z.mantissa.Set(i)
+			z.compact = c.Inflated
 		}
 		z.scale = 0
 		return z, frac
@@ -103,3 +111,319 @@ func modbig(b *big.Int, scale int32) (dec *big.Int, frac *big.Int) {
 	frac = frac.Sub(b, frac)
 	return dec, frac
 }
+
+// Mod sets z to the Euclidean modulus x mod y and returns z. The result is
+// exact and, unlike Rem, always satisfies 0 <= z < |y|. Mod implements
+// Euclidean modulus, like math/big's Int.Mod.
+//
+// Mod sets z to NaN if x is infinite or y is zero. If y is infinite and x
+// is finite, z is set to x if x >= 0 and +Inf otherwise. A zero result is
+// always +0.
+func (z *Big) Mod(x, y *Big) *Big {
+	if z.remSpecial(x, y) {
+		switch {
+		case z.isZero():
+			// -0 mod y == +0
+			z.setZero(false)
+		case z.ltz():
+			// x mod ±Inf == ±Inf + x
+			z.SetInf(false)
+		}
+		return z
+	}
+	var q Big
+	q.quoRem(x, y, z, ToZero)
+	switch {
+	case z.isZero():
+		z.setZero(false)
+	case z.ltz():
+		if y.ltz() {
+			z.Sub(z, y)
+		} else {
+			z.Add(z, y)
+		}
+	}
+	return z
+}
+
+// QuoInt sets z to the integer quotient x / y truncated toward zero and
+// returns z. The result is exact.
+//
+// QuoInt sets z to NaN if x and y are both zero or both infinite, or if the
+// quotient is too large to be represented. If only y is zero or only x is
+// infinite z is set to ±Inf, and if only y is infinite z is set to zero.
+// Dividing a finite x by zero raises DivisionByZero.
+func (z *Big) QuoInt(x, y *Big) *Big {
+	switch {
+	case z.checkNaNs(x, y):
+		return z
+	case x.isZero() && y.isZero():
+		// ±0 / ±0
+		return z.setNaN(QuoZeroZero)
+	case x.form == inf && y.form == inf:
+		// ±Inf / ±Inf
+		return z.setNaN(QuoInfInf)
+	case x.form == inf, y.isZero():
+		// ±Inf / y
+		// x / ±0
+		if x.form == finite {
+			z.raise(DivisionByZero)
+		}
+		return z.SetInf(x.SignBit() != y.SignBit())
+	case y.form == inf:
+		// x / ±Inf
+		return z.setZero(x.SignBit() != y.SignBit())
+	}
+	return z.quoRem(x, y, nil, ToZero)
+}
+
+// QuoRem sets z to the integer quotient x / y truncated toward zero, sets r
+// to the remainder x - y * z, and returns the pair (z, r). Both results are
+// exact and r has the same sign as x. QuoRem implements truncated division,
+// like math/big's Int.QuoRem.
+//
+// QuoRem sets z and r to NaN if x is infinite or y is zero. If y is
+// infinite and x is finite, z is set to zero and r is set to x.
+func (z *Big) QuoRem(x, y, r *Big) (*Big, *Big) {
+	if r.remSpecial(x, y) {
+		if r.IsNaN() {
+			z.setNaN(r.Payload())
+		} else {
+			z.setZero(x.SignBit() != y.SignBit())
+		}
+		return z, r
+	}
+	return z.quoRem(x, y, r, ToZero), r
+}
+
+// Rem sets z to the remainder x - y * q, where q is the integer quotient
+// x / y truncated toward zero, and returns z. The result is exact and has
+// the same sign as x.
+//
+// Rem sets z to NaN if x is infinite or y is zero. If y is infinite and x is
+// finite, z is set to x.
+func (z *Big) Rem(x, y *Big) *Big {
+	if z.remSpecial(x, y) {
+		return z
+	}
+	var q Big
+	q.quoRem(x, y, z, ToZero)
+	return z
+}
+
+// RemNear sets z to the IEEE 754 remainder x - y * n, where n is the integer
+// nearest x / y (the even integer if x / y is halfway between two integers),
+// and returns z. The result is exact and |z| <= |y| / 2.
+//
+// Special cases are the same as for Rem.
+func (z *Big) RemNear(x, y *Big) *Big {
+	if z.remSpecial(x, y) {
+		return z
+	}
+	var q Big
+	q.quoRem(x, y, z, ToNearestEven)
+	return z
+}
+
+// remSpecial handles the special cases for the remainder functions. It
+// returns true if x or y was a special case and z has been set to the
+// result.
+func (z *Big) remSpecial(x, y *Big) bool {
+	switch {
+	case z.checkNaNs(x, y):
+		return true
+	case x.form == inf, y.isZero():
+		// ±Inf rem y
+		// x rem ±0
+		z.setNaN(RemInfOrZero)
+		return true
+	case y.form == inf:
+		// x rem ±Inf == x
+		ctx := z.ctx
+		z.Set(x)
+		z.ctx = ctx
+		return true
+	case x.isZero():
+		// ±0 rem y == ±0
+		z.setZero(x.SignBit())
+		return true
+	}
+	return false
+}
+
+// quoRem sets z to the integer quotient x / y rounded using mode, sets r (if
+// r is non-nil) to the remainder x - y * z, and returns z. The results are
+// exact. mode must be either ToZero or ToNearestEven. x and y must be finite
+// and y must be nonzero. A zero quotient is negative if x and y have
+// different signs, and a zero remainder has the sign of x.
+func (z *Big) quoRem(x, y, r *Big, mode RoundingMode) *Big {
+	neg := x.SignBit() != y.SignBit()
+	// If |x| < |y| (or |x| < |y| / 2 when rounding to nearest) the quotient
+	// is zero. Checking this first means we don't have to scale y up to x's
+	// scale if the two are far apart.
+	ya := y.adjusted()
+	if mode == ToNearestEven {
+		ya--
+	}
+	if x.isZero() || x.adjusted() < ya {
+		if r != nil {
+			ctx := r.ctx
+			r.Set(x)
+			r.ctx = ctx
+		}
+		return z.setZero(neg)
+	}
+
+	// Give x and y the same scale so that x / y == xm / ym.
+	scale := x.scale
+	if y.scale > scale {
+		scale = y.scale
+	}
+	xs, ok1 := checked.Sub32(scale, x.scale)
+	ys, ok2 := checked.Sub32(scale, y.scale)
+	if !ok1 || !ok2 {
+		if r != nil {
+			r.setNaN(QuoIntImpossible)
+		}
+		return z.setNaN(QuoIntImpossible)
+	}
+
+	if x.isCompact() && y.isCompact() {
+		xm, ok1 := checked.MulPow10(x.compact, xs)
+		ym, ok2 := checked.MulPow10(y.compact, ys)
+		// math.MinInt64 / -1 overflows, as does doubling the remainder if
+		// |ym| is too large.
+		ok := ok1 && ok2 && (xm != math.MinInt64 || ym != -1) &&
+			(mode == ToZero || (ym > math.MinInt64/2 && ym < math.MaxInt64/2))
+		if ok {
+			q, rm := xm/ym, xm%ym
+			if rm != 0 && mode == ToNearestEven &&
+				mode.needsInc(arith.AbsCmp(rm*2, ym), (xm < 0) == (ym < 0), arith.Abs(q%10)) {
+				if (xm < 0) == (ym < 0) {
+					q++
+					rm -= ym
+				} else {
+					q--
+					rm += ym
+				}
+			}
+			if r != nil {
+				r.SetMantScale(rm, scale)
+				if rm == 0 {
+					r.setZero(x.SignBit())
+				}
+			}
+			if q == 0 {
+				return z.setZero(neg)
+			}
+			return z.SetMantScale(q, 0)
+		}
+	}
+
+	var xm, ym, q, rm big.Int
+	if x.isCompact() {
+		xm.SetInt64(x.compact)
+	} else {
+		xm.Set(&x.mantissa)
+	}
+	if y.isCompact() {
+		ym.SetInt64(y.compact)
+	} else {
+		ym.Set(&y.mantissa)
+	}
+	checked.MulBigPow10(&xm, xs)
+	checked.MulBigPow10(&ym, ys)
+	q.QuoRem(&xm, &ym, &rm)
+	if rm.Sign() != 0 && mode == ToNearestEven {
+		var r2 big.Int
+		pos := xm.Sign() == ym.Sign()
+		if mode.needsInc(arith.BigAbsCmp(*r2.Lsh(&rm, 1), ym), pos, int64(q.Bit(0))) {
+			if pos {
+				q.Add(&q, oneInt)
+				rm.Sub(&rm, &ym)
+			} else {
+				q.Sub(&q, oneInt)
+				rm.Add(&rm, &ym)
+			}
+		}
+	}
+	if r != nil {
+		r.SetBigMantScale(&rm, scale)
+		if rm.Sign() == 0 {
+			r.setZero(x.SignBit())
+		}
+	}
+	if q.Sign() == 0 {
+		return z.setZero(neg)
+	}
+	return z.SetBigMantScale(&q, 0)
+}
+
+// Max returns the largest of the provided values, following the IEEE
+// 754-2008 maxNum operation. If several values are numerically equal but
+// have different scales, it returns the one with the smallest scale if they
+// are positive and the largest scale if they are negative. Of values that
+// are identical, it returns the first. Quiet NaN values are ignored unless
+// every value is NaN. If any value is a signaling NaN, Max returns a new
+// quiet NaN with the payload of the first one and raises InvalidOperation in
+// its Context, which is a copy of the signaling NaN's. Max returns nil if no
+// values are provided.
+func Max(x ...*Big) *Big {
+	return pick(x, func(a, b *Big) bool { return a.CmpTotal(b) > 0 })
+}
+
+// MaxMag returns the value with the largest magnitude, following the IEEE
+// 754-2008 maxNumMag operation. Values with the same magnitude are ordered as
+// in Max. MaxMag returns nil if no values are provided.
+func MaxMag(x ...*Big) *Big {
+	return pick(x, func(a, b *Big) bool {
+		if c := a.CmpAbs(b); c != 0 {
+			return c > 0
+		}
+		return a.CmpTotal(b) > 0
+	})
+}
+
+// Min returns the smallest of the provided values, following the IEEE
+// 754-2008 minNum operation. If several values are numerically equal but
+// have different scales, it returns the one with the largest scale if they
+// are positive and the smallest scale if they are negative. Of values that
+// are identical, it returns the first. NaN values are handled as in Max. Min
+// returns nil if no values are provided.
+func Min(x ...*Big) *Big {
+	return pick(x, func(a, b *Big) bool { return a.CmpTotal(b) < 0 })
+}
+
+// MinMag returns the value with the smallest magnitude, following the IEEE
+// 754-2008 minNumMag operation. Values with the same magnitude are ordered as
+// in Min. MinMag returns nil if no values are provided.
+func MinMag(x ...*Big) *Big {
+	return pick(x, func(a, b *Big) bool {
+		if c := a.CmpAbs(b); c != 0 {
+			return c < 0
+		}
+		return a.CmpTotal(b) < 0
+	})
+}
+
+// pick returns the first element of x for which no later element is better.
+// Quiet NaNs are skipped and the first signaling NaN, if any, is quieted
+// immediately.
+func pick(x []*Big, better func(a, b *Big) bool) *Big {
+	var m *Big
+	for _, v := range x {
+		switch {
+		case v.form == snan:
+			return new(Big).SetContext(v.ctx).setNaN(v.Payload())
+		case v.form == qnan:
+			continue
+		case m == nil || better(v, m):
+			m = v
+		}
+	}
+	if m == nil && len(x) > 0 {
+		// Every value is a quiet NaN.
+		return x[0]
+	}
+	return m
+}
@@ -22,7 +22,7 @@ func Hypot(z, p, q *decimal.Big) *decimal.Big {
 
 // Sqrt sets z to the square root of x and returns z.
 // The precision of Sqrt is determined by z's Context.
-// Sqrt will panic on negative values since decimal.Big cannot
+// Sqrt sets z to NaN on negative values since decimal.Big cannot
 // represent imaginary numbers.
 func Sqrt(z, x *decimal.Big) *decimal.Big {
 	return z.Sqrt(x)
@@ -12,7 +12,7 @@ func TestDecimal_Hypot(t *testing.T) {
 		c    int32
 		a    string
 	}{
-		{decimal.New(1, 0), decimal.New(4, 0), 15, "4.12310562561766"},
+		{decimal.New(1, 0), decimal.New(4, 0), 15, "4.123105625617661"},
 		{decimal.New(1, 0), decimal.New(4, 0), 10, "4.1231056256"},
 		{Pi, Pi, 75, "4.442882938158366247015880990060693698614621689375690223085395606956434793099"},
 		{decimal.New(-12, 0), decimal.New(599, 0), 2, "599.12"},
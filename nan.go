@@ -0,0 +1,101 @@
+package decimal
+
+// Payload is a NaN value's payload. Payloads set by SetString or SetNaN are
+// arbitrary, but the NaN values created by invalid operations carry one of
+// the diagnostic payloads below, which describe the operation.
+type Payload uint64
+
+// Diagnostic payloads for NaN values created by invalid operations.
+const (
+	AddInfSubInf     Payload = iota + 1 // addition of infinities with opposing signs
+	MulZeroInf                          // multiplication of zero with infinity
+	QuoZeroZero                         // division of zero by zero
+	QuoInfInf                           // division of infinity by infinity
+	QuantizeInf                         // quantization of an infinity
+	QuantizeMinMax                      // quantization result exceeds precision
+	QuoIntImpossible                    // integer division result exceeds precision
+	RemInfOrZero                        // remainder of infinity or by zero
+	SqrtNegative                        // square root of a negative number
+	LogNegative                         // logarithm of a negative number
+	PowNegNonInt                        // power of x < 0 with non-integer exponent
+	SubInfInf                           // subtraction of infinities with equal signs
+)
+
+var payloads = [...]string{
+	AddInfSubInf:     "addition of infinities with opposing signs",
+	MulZeroInf:       "multiplication of zero with infinity",
+	QuoZeroZero:      "division of zero by zero",
+	QuoInfInf:        "division of infinity by infinity",
+	QuantizeInf:      "quantization of an infinity",
+	QuantizeMinMax:   "quantization result exceeds precision",
+	QuoIntImpossible: "integer division result exceeds precision",
+	RemInfOrZero:     "remainder of infinity or by zero",
+	SqrtNegative:     "square root of a negative number",
+	LogNegative:      "logarithm of a negative number",
+	PowNegNonInt:     "power of x < 0 with non-integer exponent",
+	SubInfInf:        "subtraction of infinities with equal signs",
+}
+
+func (p Payload) String() string {
+	if p > 0 && p < Payload(len(payloads)) {
+		return payloads[p]
+	}
+	return "unknown NaN payload"
+}
+
+// IsNaN returns true if x is a quiet or signaling NaN.
+func (x *Big) IsNaN() bool {
+	return x.form == qnan || x.form == snan
+}
+
+// IsSignaling returns true if x is a signaling NaN.
+func (x *Big) IsSignaling() bool {
+	return x.form == snan
+}
+
+// Payload returns the payload of x if x is NaN and 0 otherwise.
+func (x *Big) Payload() Payload {
+	if !x.IsNaN() {
+		return 0
+	}
+	return Payload(x.compact)
+}
+
+// SetNaN sets z to a quiet NaN, or a signaling NaN if signal is true, with
+// the given payload and returns z.
+func (z *Big) SetNaN(signal bool, payload Payload) *Big {
+	z.form = qnan
+	if signal {
+		z.form = snan
+	}
+	z.compact = int64(payload)
+	return z
+}
+
+// setNaN sets z to a quiet NaN with the given diagnostic payload, raises
+// InvalidOperation, and returns z.
+func (z *Big) setNaN(p Payload) *Big {
+	return z.SetNaN(false, p).raise(InvalidOperation)
+}
+
+// checkNaNs reports whether any of the operands is NaN. If so, it sets z to
+// the quiet NaN that results from an operation on them: the payload comes
+// from the first signaling NaN or, if there's none, the first quiet NaN.
+// Only signaling NaNs raise InvalidOperation.
+func (z *Big) checkNaNs(x ...*Big) bool {
+	var q *Big
+	for _, v := range x {
+		if v.form == snan {
+			z.setNaN(v.Payload())
+			return true
+		}
+		if q == nil && v.form == qnan {
+			q = v
+		}
+	}
+	if q == nil {
+		return false
+	}
+	z.SetNaN(false, q.Payload())
+	return true
+}
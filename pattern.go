@@ -0,0 +1,529 @@
+package decimal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pattern is a compiled number pattern in the style of ICU's DecimalFormat
+// and spreadsheet number formats, like "#,##0.00;(#,##0.00)", "0.###E0" or
+// "0.00%".
+//
+// A pattern is a positive subpattern optionally followed by ';' and a
+// negative subpattern. Each subpattern is a prefix, a number part and a
+// suffix. The number part is made of the following characters:
+//
+//	0	a digit, written even if it's a leading or trailing zero
+//	#	a digit, omitted if it's a leading or trailing zero
+//	.	the decimal mark
+//	,	the grouping separator
+//	E	starts the exponent of scientific notation. It's followed by an
+//		optional '+', to write the sign of positive exponents, and one or
+//		more '0's, the minimum number of exponent digits
+//
+// The integer part's '#'s must precede its '0's, and the fraction part's
+// '0's must precede its '#'s. The size of the groups of integer digits is
+// the number of digits between the last ',' and the decimal mark. If there
+// are two or more ','s, the number of digits between the last two is the
+// size of the rest of the groups, so "#,##,##0" groups as in India.
+//
+// The prefix and suffix are literal text, except for these characters:
+//
+//	%	multiplies the number by 100 and is written as is
+//	‰	multiplies the number by 1000 and is written as is
+//	-	the minus sign
+//	*c	pads the result with c to the width of the positive subpattern
+//	'	quotes literal text, and '' is a single quote
+//
+// Digits, '#', '@', '.' and ',' must be quoted to be used in a prefix or
+// suffix. Padding goes where '*c' appears, which may be before or after the
+// prefix or the suffix. The width is the number of characters in the
+// positive subpattern without the '*c' and the quotes.
+//
+// Only the prefix and suffix of the negative subpattern are used. Without a
+// negative subpattern, negative numbers are written with a minus sign before
+// the positive prefix.
+//
+// In scientific notation the mantissa has at most the minimum number of
+// integer digits plus the maximum number of fraction digits as significant
+// digits. If the maximum number of integer digits is greater than both the
+// minimum and 1 the exponent is a multiple of it, so "##0.###E0" writes
+// engineering notation. Otherwise the mantissa has the minimum number of
+// integer digits.
+type Pattern struct {
+	src string
+
+	// The prefixes and suffixes of positive and negative numbers.
+	posPrefix, posSuffix affix
+	negPrefix, negSuffix affix
+
+	minInt, maxInt   int
+	minFrac, maxFrac int
+	grouping         []int // as in Locale; nil if there's no grouping
+
+	sci     bool // scientific notation
+	expPlus bool // write the sign of positive exponents
+	minExp  int  // minimum number of exponent digits
+
+	mult int32 // power of ten the number is multiplied by
+
+	pad    string // the padding character, or "" if there's no padding
+	padPos padPosition
+	width  int
+}
+
+// padPosition is where a Pattern's padding goes.
+type padPosition uint8
+
+const (
+	padBeforePrefix padPosition = iota
+	padAfterPrefix
+	padBeforeSuffix
+	padAfterSuffix
+)
+
+// affix is a prefix or suffix. Its minus signs are written using a Locale.
+type affix []affixPart
+
+type affixPart struct {
+	lit   string
+	minus bool
+}
+
+// add appends the literal text s to a.
+func (a affix) add(s string) affix {
+	if n := len(a); n > 0 && !a[n-1].minus {
+		a[n-1].lit += s
+		return a
+	}
+	return append(a, affixPart{lit: s})
+}
+
+// text returns a as written using l.
+func (a affix) text(l Locale) string {
+	var s string
+	for _, v := range a {
+		if v.minus {
+			s += l.minus()
+		} else {
+			s += v.lit
+		}
+	}
+	return s
+}
+
+// CompilePattern parses a pattern and returns, if successful, a Pattern that
+// can be used to format decimals.
+func CompilePattern(pattern string) (*Pattern, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("decimal: invalid pattern %q: %v", pattern, err)
+	}
+	return p, nil
+}
+
+func compilePattern(pattern string) (*Pattern, error) {
+	pos, neg, err := splitPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pattern{src: pattern}
+	if err := p.parse(pos); err != nil {
+		return nil, err
+	}
+	if neg == "" {
+		p.negPrefix = append(affix{{minus: true}}, p.posPrefix...)
+		p.negSuffix = p.posSuffix
+		return p, nil
+	}
+	var n Pattern
+	if err := n.parse(neg); err != nil {
+		return nil, err
+	}
+	p.negPrefix, p.negSuffix = n.posPrefix, n.posSuffix
+	return p, nil
+}
+
+// MustCompilePattern is like CompilePattern but panics if the pattern can't
+// be parsed.
+func MustCompilePattern(pattern string) *Pattern {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source text of p.
+func (p *Pattern) String() string {
+	return p.src
+}
+
+// splitPattern splits s into its positive and negative subpatterns.
+func splitPattern(s string) (pos, neg string, err error) {
+	i, quoted := -1, false
+	for j := 0; j < len(s); j++ {
+		switch {
+		case s[j] == '*' && !quoted:
+			// The padding character is never a quote or separator.
+			j++
+		case s[j] == '\'':
+			quoted = !quoted
+		case s[j] == ';' && !quoted:
+			if i >= 0 {
+				return "", "", errors.New("more than two subpatterns")
+			}
+			i = j
+		}
+	}
+	switch {
+	case quoted:
+		return "", "", errors.New("unterminated quote")
+	case i < 0:
+		return s, "", nil
+	case i == len(s)-1:
+		return "", "", errors.New("empty negative subpattern")
+	}
+	return s[:i], s[i+1:], nil
+}
+
+// parse parses the subpattern s into p's positive prefix and suffix, number
+// format, multiplier and padding.
+func (p *Pattern) parse(s string) error {
+	i, err := p.parseAffix(s, 0, &p.posPrefix, true)
+	if err != nil {
+		return err
+	}
+	if i == len(s) {
+		return errors.New("missing number")
+	}
+	j, err := p.parseNumber(s, i)
+	if err != nil {
+		return err
+	}
+	p.width += j - i
+	if j, err = p.parseAffix(s, j, &p.posSuffix, false); err != nil {
+		return err
+	}
+	if j != len(s) {
+		return fmt.Errorf("unexpected %q", s[j:])
+	}
+	return nil
+}
+
+// parseAffix parses the prefix or suffix of s starting at s[i] into a and
+// returns the index of the first byte after it. A prefix ends at the start
+// of the number part.
+func (p *Pattern) parseAffix(s string, i int, a *affix, prefix bool) (int, error) {
+	start := i
+	for i < len(s) {
+		c, size := utf8.DecodeRuneInString(s[i:])
+		switch c {
+		case '\'':
+			lit, j := unquote(s, i)
+			*a = a.add(lit)
+			p.width += utf8.RuneCountInString(lit)
+			i = j
+			continue
+		case '*':
+			if p.pad != "" {
+				return 0, errors.New("more than one padding")
+			}
+			_, n := utf8.DecodeRuneInString(s[i+size:])
+			if n == 0 {
+				return 0, errors.New("missing padding character")
+			}
+			p.pad = s[i+size : i+size+n]
+			end := i + size + n
+			switch {
+			case prefix && i == start:
+				p.padPos = padBeforePrefix
+			case prefix && end < len(s) && isNumberStart(s[end]):
+				p.padPos = padAfterPrefix
+			case !prefix && i == start:
+				p.padPos = padBeforeSuffix
+			case !prefix && end == len(s):
+				p.padPos = padAfterSuffix
+			default:
+				return 0, errors.New("padding isn't next to the prefix or suffix")
+			}
+			i = end
+			continue
+		case '%', '‰':
+			if p.mult != 0 {
+				return 0, errors.New("more than one percent or per mille sign")
+			}
+			p.mult = 2
+			if c == '‰' {
+				p.mult = 3
+			}
+			*a = a.add(string(c))
+		case '-':
+			*a = append(*a, affixPart{minus: true})
+		case '#', '@', '.', ',', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			if prefix && isNumberStart(byte(c)) {
+				return i, nil
+			}
+			return 0, fmt.Errorf("unexpected %q", c)
+		default:
+			*a = a.add(string(c))
+		}
+		p.width++
+		i += size
+	}
+	return i, nil
+}
+
+// isNumberStart reports whether c can start the number part of a pattern.
+func isNumberStart(c byte) bool {
+	return c == '#' || c == '0' || c == '.' || c == ','
+}
+
+// unquote returns the quoted text starting at s[i] and the index of the
+// first byte after it. Two quotes in a row, inside the quotes or on their
+// own, are a single quote. s must have no unterminated quotes.
+func unquote(s string, i int) (string, int) {
+	if s[i+1] == '\'' {
+		return "'", i + 2
+	}
+	var lit string
+	for i++; ; i++ {
+		if s[i] == '\'' {
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+			} else {
+				return lit, i + 1
+			}
+		}
+		lit += s[i : i+1]
+	}
+}
+
+// parseNumber parses the number part of s starting at s[i] into p and
+// returns the index of the first byte after it.
+func (p *Pattern) parseNumber(s string, i int) (int, error) {
+	var (
+		intHash, intZero   int
+		fracZero, fracHash int
+		commas             []int // the number of integer digits before each ','
+	)
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '#':
+			if intZero > 0 {
+				return 0, errors.New("'#' after '0' in the integer part")
+			}
+			intHash++
+			continue
+		case '0':
+			intZero++
+			continue
+		case ',':
+			commas = append(commas, intHash+intZero)
+			continue
+		}
+		break
+	}
+	if i < len(s) && s[i] == '.' {
+		for i++; i < len(s); i++ {
+			switch s[i] {
+			case '0':
+				if fracHash > 0 {
+					return 0, errors.New("'0' after '#' in the fraction part")
+				}
+				fracZero++
+				continue
+			case '#':
+				fracHash++
+				continue
+			case '.':
+				return 0, errors.New("more than one decimal mark")
+			case ',':
+				return 0, errors.New("grouping separator in the fraction part")
+			}
+			break
+		}
+	}
+	if i < len(s) && s[i] == 'E' {
+		p.sci = true
+		i++
+		if i < len(s) && s[i] == '+' {
+			p.expPlus = true
+			i++
+		}
+		for ; i < len(s) && s[i] == '0'; i++ {
+			p.minExp++
+		}
+		if p.minExp == 0 {
+			return 0, errors.New("missing exponent digits")
+		}
+	}
+
+	p.minInt, p.maxInt = intZero, intHash+intZero
+	p.minFrac, p.maxFrac = fracZero, fracZero+fracHash
+	if p.maxInt+p.maxFrac == 0 {
+		return 0, errors.New("missing digits")
+	}
+	if n := len(commas); n > 0 {
+		if p.sci {
+			return 0, errors.New("grouping separator in scientific notation")
+		}
+		primary := p.maxInt - commas[n-1]
+		if primary == 0 {
+			return 0, errors.New("empty group")
+		}
+		p.grouping = []int{primary}
+		if n > 1 {
+			secondary := commas[n-1] - commas[n-2]
+			if secondary == 0 {
+				return 0, errors.New("empty group")
+			}
+			if secondary != primary {
+				p.grouping = append(p.grouping, secondary)
+			}
+		}
+	}
+	return i, nil
+}
+
+// Format returns x formatted using p. Digits that don't fit are rounded
+// using x's RoundingMode, or ToNearestEven if it's Unneeded, without raising
+// any conditions. ±Inf is written as Inf with the prefix and suffix, and NaN
+// as in String.
+func (p *Pattern) Format(x *Big) string {
+	return p.FormatLocale(x, Locale{})
+}
+
+// FormatLocale is like Format, but uses l's decimal mark, grouping separator,
+// minus sign and digits. The sizes of the groups of digits are p's, and l's
+// MinusStyle is ignored.
+func (p *Pattern) FormatLocale(x *Big, l Locale) string {
+	if x == nil || x.IsNaN() {
+		return x.String()
+	}
+	prefix, suffix := p.posPrefix, p.posSuffix
+	if x.SignBit() {
+		prefix, suffix = p.negPrefix, p.negSuffix
+	}
+	pre, num, suf := prefix.text(l), p.number(x, l), suffix.text(l)
+
+	var padding string
+	if n := p.width - utf8.RuneCountInString(pre) - utf8.RuneCountInString(num) -
+		utf8.RuneCountInString(suf); p.pad != "" && n > 0 {
+		padding = strings.Repeat(p.pad, n)
+	}
+	switch p.padPos {
+	case padBeforePrefix:
+		return padding + pre + num + suf
+	case padAfterPrefix:
+		return pre + padding + num + suf
+	case padBeforeSuffix:
+		return pre + num + padding + suf
+	default:
+		return pre + num + suf + padding
+	}
+}
+
+// number returns |x| formatted as p's number part using l.
+func (p *Pattern) number(x *Big, l Locale) string {
+	// Only x's RoundingMode is used, so Scalb doesn't round to x's
+	// precision or exponent limits.
+	var t Big
+	t.ctx.Mode = x.ctx.Mode
+	if t.ctx.Mode == Unneeded {
+		t.ctx.Mode = ToNearestEven
+	}
+	t.Scalb(x, p.mult)
+	if t.IsInf() {
+		return "Inf"
+	}
+
+	var ip, fp string
+	var exp int64
+	if p.sci {
+		eng := p.maxInt > p.minInt && p.maxInt > 1
+		minInt := p.minInt
+		if eng || minInt == 0 {
+			minInt = 1
+		}
+		t.roundToPrec(int32(minInt + p.maxFrac))
+
+		d, dexp := t.digits()
+		switch {
+		case d == "":
+			d = "0"
+		case eng:
+			// The adjusted exponent rounded down to a multiple of maxInt.
+			exp = dexp - 1
+			if m := exp % int64(p.maxInt); m < 0 {
+				exp -= m + int64(p.maxInt)
+			} else {
+				exp -= m
+			}
+			minInt = int(dexp - exp)
+		default:
+			exp = dexp - int64(minInt)
+		}
+		if len(d) < minInt {
+			d += strings.Repeat("0", minInt-len(d))
+		}
+		ip, fp = d[:minInt], d[minInt:]
+	} else {
+		s := string(t.append(nil, 'f', p.maxFrac, false))
+		if s[0] == '-' {
+			s = s[1:]
+		}
+		ip = s
+		if i := strings.IndexByte(s, '.'); i >= 0 {
+			ip, fp = s[:i], s[i+1:]
+		}
+		ip = strings.TrimLeft(ip, "0")
+		if len(ip) < p.minInt {
+			ip = strings.Repeat("0", p.minInt-len(ip)) + ip
+		}
+	}
+	for len(fp) > p.minFrac && fp[len(fp)-1] == '0' {
+		fp = fp[:len(fp)-1]
+	}
+	if len(fp) < p.minFrac {
+		fp += strings.Repeat("0", p.minFrac-len(fp))
+	}
+	if ip == "" && fp == "" {
+		ip = "0"
+	}
+
+	var b bytes.Buffer
+	g := Locale{Group: l.Group, Grouping: p.grouping, Digits: l.Digits}
+	if g.Group == "" {
+		g.Group = ","
+	}
+	g.writeGrouped(&b, ip)
+	if fp != "" {
+		if l.Decimal != "" {
+			b.WriteString(l.Decimal)
+		} else {
+			b.WriteByte('.')
+		}
+		l.writeDigits(&b, fp)
+	}
+	if p.sci {
+		b.WriteByte('E')
+		switch {
+		case exp < 0:
+			b.WriteString(l.minus())
+			exp = -exp
+		case p.expPlus:
+			b.WriteByte('+')
+		}
+		e := strconv.FormatInt(exp, 10)
+		if len(e) < p.minExp {
+			e = strings.Repeat("0", p.minExp-len(e)) + e
+		}
+		l.writeDigits(&b, e)
+	}
+	return b.String()
+}
@@ -5,45 +5,410 @@ import (
 	"math/big"
 
 	"github.com/EricLagergren/decimal/internal/arith"
+	"github.com/EricLagergren/decimal/internal/arith/checked"
+	"github.com/EricLagergren/decimal/internal/arith/pow"
+	"github.com/EricLagergren/decimal/internal/c"
 )
 
-func (r RoundingMode) needsInc(c int, pos, odd bool) bool {
+// needsInc reports whether a result truncated toward zero must be incremented
+// in magnitude to round it using r. c is the comparison of the discarded
+// fraction with one half (-1, 0, or +1), pos reports whether the result is
+// positive, and d is the absolute value of the truncated result's last digit.
+func (r RoundingMode) needsInc(c int, pos bool, d int64) bool {
 	switch r {
 	case Unneeded:
-		panic("decimal: rounding is necessary")
+		panic(Inexact)
 	case AwayFromZero:
 		return true
 	case ToZero:
 		return false
+	case AwayFromZero05:
+		return d == 0 || d == 5
 	case ToPositiveInf:
 		return pos
 	case ToNegativeInf:
 		return !pos
-	case ToNearestEven, ToNearestAway:
+	case ToNearestEven, ToNearestAway, ToNearestZero:
 		if c < 0 {
 			return false
 		}
 		if c > 0 {
 			return true
 		}
-		if r == ToNearestEven {
-			return odd
+		switch r {
+		case ToNearestEven:
+			return d&1 != 0
+		case ToNearestZero:
+			return false
 		}
 		return true
 	}
 	panic("unknown RoundingMode")
 }
 
-func (z *Big) needsInc(x, r int64, pos, odd bool) bool {
+func (z *Big) needsInc(x, r int64, pos bool, d int64) bool {
 	m := 1
 	if r > math.MinInt64/2 || r <= math.MaxInt64/2 {
 		m = arith.AbsCmp(r<<1, x)
 	}
-	return z.ctx.mode.needsInc(m, pos, odd)
+	return z.ctx.Mode.needsInc(m, pos, d)
 }
 
-func (z *Big) needsIncBig(x, r *big.Int, pos, odd bool) bool {
+func (z *Big) needsIncBig(x, r *big.Int, pos bool, d int64) bool {
 	var x0 big.Int
 	m := arith.BigAbsCmp(*x0.Mul(r, twoInt), *x)
-	return z.ctx.mode.needsInc(m, pos, odd)
+	return z.ctx.Mode.needsInc(m, pos, d)
+}
+
+// roundToPrec rounds z to n digits of precision using z's RoundingMode and
+// returns z. Unlike Round, it rounds digits to the left of the radix as well.
+// No rounding will occur if n is zero.
+func (z *Big) roundToPrec(n int32) *Big {
+	if n <= 0 || z.form != finite || z.Prec() <= int(n) {
+		return z
+	}
+	z.shrink(int64(z.Prec())-int64(n), z.ctx.Mode)
+	// Rounding away from zero could have added a digit. E.g., 999 -> 1000.
+	// The extra digit is always a zero, so removing it is exact.
+	if z.Prec() > int(n) {
+		z.shrink(1, z.ctx.Mode)
+	}
+	return z
+}
+
+// fix rounds z to its Context's precision and, if the Context has exponent
+// limits, brings z's exponent into range, raising the appropriate
+// conditions. It returns z. Values whose adjusted exponent is less than Emin
+// are rounded to fewer digits so that their exponent is at least Etiny,
+// Emin - Precision + 1, so z is only rounded once.
+func (z *Big) fix() *Big {
+	if z.form != finite && z.form != zero {
+		return z
+	}
+	if !z.ctx.limited() {
+		return z.roundToPrec(z.ctx.prec())
+	}
+
+	prec := int64(z.ctx.prec())
+	etiny, etop := z.ctx.etiny(), z.ctx.etop()
+	exp := -int64(z.scale)
+
+	if z.isZero() {
+		max := int64(z.ctx.Emax)
+		if z.ctx.Clamp {
+			max = etop
+		}
+		switch {
+		case exp < etiny:
+			exp = etiny
+		case exp > max:
+			exp = max
+		default:
+			return z
+		}
+		z.scale = int32(-exp)
+		return z.raise(Clamped)
+	}
+
+	neg := z.SignBit()
+	adj := z.adjusted()
+	if adj > int64(z.ctx.Emax) {
+		return z.overflow(neg)
+	}
+
+	subnormal := adj < int64(z.ctx.Emin)
+	min := adj - prec + 1
+	if subnormal {
+		min = etiny
+	}
+	if exp < min {
+		// Keep track of whether shrink discards nonzero digits.
+		c := z.ctx.Conditions
+		z.ctx.Conditions &^= Inexact
+		z.shrink(min-exp, z.ctx.Mode)
+		inexact := z.ctx.Conditions&Inexact != 0
+		z.ctx.Conditions |= c
+		if int64(z.Prec()) > prec {
+			// Rounding away from zero added a digit. E.g., 999 -> 1000.
+			z.shrink(1, z.ctx.Mode)
+		}
+		if -int64(z.scale) > etop {
+			return z.overflow(neg)
+		}
+		if subnormal {
+			z.raise(Subnormal)
+			if inexact {
+				z.raise(Underflow)
+			}
+			if z.isZero() {
+				z.raise(Clamped)
+			}
+		}
+		return z
+	}
+
+	if subnormal {
+		z.raise(Subnormal)
+	}
+	if z.ctx.Clamp && exp > etop {
+		// Pad the coefficient with zeros so the exponent is etop. The value
+		// has at most prec digits afterward since adj <= Emax.
+		if z.isCompact() {
+			z.mantissa.SetInt64(z.compact)
+			z.compact = c.Inflated
+		}
+		checked.MulBigPow10(&z.mantissa, int32(exp-etop))
+		if z.mantissa.IsInt64() && z.mantissa.Int64() != c.Inflated {
+			z.compact = z.mantissa.Int64()
+		}
+		z.scale = int32(-etop)
+		z.raise(Clamped)
+	}
+	return z
+}
+
+// finish rounds the exact result of an operation that's otherwise exact, like
+// Add, as z's Context requires and returns z: to its Scale if it's fixed, or
+// to its precision and exponent limits if RoundAll is true.
+func (z *Big) finish() *Big {
+	switch {
+	case z.ctx.Fixed:
+		return z.fixScale()
+	case z.ctx.RoundAll:
+		return z.fix()
+	}
+	return z
+}
+
+// fixScale rounds z to its Context's Scale using its RoundingMode, or pads it
+// with zeros, if its Context has a fixed scale. It returns z.
+func (z *Big) fixScale() *Big {
+	if !z.ctx.Fixed {
+		return z
+	}
+	switch z.form {
+	case zero:
+		z.scale = z.ctx.Scale
+	case finite:
+		if z.scale > z.ctx.Scale {
+			return z.shrink(int64(z.scale)-int64(z.ctx.Scale), z.ctx.Mode)
+		}
+		shift, ok := checked.Sub32(z.ctx.Scale, z.scale)
+		if !ok {
+			return z.overflow(z.SignBit())
+		}
+		z.pad(shift)
+	}
+	return z
+}
+
+// pad multiplies z's mantissa by 10 ** n, n >= 0, and increases its scale by
+// n without changing its value. It returns z.
+func (z *Big) pad(n int32) *Big {
+	if n == 0 {
+		return z
+	}
+	if z.isCompact() {
+		if m, ok := checked.MulPow10(z.compact, n); ok {
+			z.compact = m
+			z.scale += n
+			return z
+		}
+		z.mantissa.SetInt64(z.compact)
+		z.compact = c.Inflated
+	}
+	checked.MulBigPow10(&z.mantissa, n)
+	z.scale += n
+	return z
+}
+
+// reround adds one unit in the last place of z away from zero if z's last
+// digit is 0 or 5, and returns z. neg is z's sign. It's used on a quotient
+// that was truncated with extra digits and is inexact, so the extra digits
+// are never mistaken for an exact value or a tie when the quotient is
+// rounded again. The increment can't carry since the last digit is 0 or 5.
+func (z *Big) reround(neg bool) *Big {
+	if d := z.lastDigit(); d != 0 && d != 5 {
+		return z
+	}
+	switch {
+	case z.isCompact() && neg:
+		z.compact--
+	case z.isCompact():
+		z.compact++
+	case neg:
+		z.mantissa.Sub(&z.mantissa, oneInt)
+	default:
+		z.mantissa.Add(&z.mantissa, oneInt)
+	}
+	return z
+}
+
+// strip removes up to n trailing zeros from z's mantissa, decreasing its scale
+// so its value doesn't change, and returns z. z must be finite.
+func (z *Big) strip(n int64) *Big {
+	for ; n > 0 && z.lastDigit() == 0 && z.scale > MinScale; n-- {
+		if z.isCompact() {
+			z.compact /= 10
+		} else {
+			z.mantissa.Quo(&z.mantissa, c.TenInt)
+		}
+		z.scale--
+	}
+	return z
+}
+
+// setMax sets z to the finite value with the largest magnitude allowed by
+// z's Context, negated if neg is true, and returns z.
+func (z *Big) setMax(neg bool) *Big {
+	prec := int64(z.ctx.prec())
+	var m big.Int
+	p := pow.BigTen(prec)
+	m.Sub(&p, oneInt)
+	if neg {
+		m.Neg(&m)
+	}
+	z.SetBigMantScale(&m, int32(-z.ctx.etop()))
+	if m.IsInt64() && m.Int64() != c.Inflated {
+		z.compact = m.Int64()
+	}
+	return z
+}
+
+// shrink divides z's mantissa by 10 ** n, n > 0, rounding the quotient using
+// mode. It decreases z's scale by n and returns z. A zero quotient keeps
+// z's sign.
+func (z *Big) shrink(n int64, mode RoundingMode) *Big {
+	neg := z.SignBit()
+	scale, ok := checked.Int32(int64(z.scale) - n)
+	if !ok {
+		return z.overflow(neg)
+	}
+	z.scale = scale
+	z.raise(Rounded)
+
+	if n > int64(z.Prec()) {
+		// |z| < 10 ** n / 10, so the quotient is zero and the remainder is
+		// less than half of 10 ** n. Don't bother computing 10 ** n.
+		z.raise(Inexact)
+		if mode.needsInc(-1, z.Sign() > 0, 0) {
+			z.compact = int64(z.Sign())
+		} else {
+			z.setZero(neg)
+		}
+		return z
+	}
+
+	if z.isCompact() {
+		if p, ok := pow.Ten64(n); ok {
+			q, r := z.compact/p, z.compact%p
+			if r != 0 {
+				z.raise(Inexact)
+			}
+			// |r| < p <= 1e18, so r*2 cannot overflow.
+			if r != 0 && mode.needsInc(arith.AbsCmp(r*2, p), z.compact > 0, arith.Abs(q%10)) {
+				if z.compact > 0 {
+					q++
+				} else {
+					q--
+				}
+			}
+			z.compact = q
+			if q == 0 {
+				z.setZero(neg)
+			}
+			return z
+		}
+		z.mantissa.SetInt64(z.compact)
+		z.compact = c.Inflated
+	}
+
+	p := pow.BigTen(n)
+	pos := z.mantissa.Sign() > 0
+	var r big.Int
+	z.mantissa.QuoRem(&z.mantissa, &p, &r)
+	if r.Sign() != 0 {
+		z.raise(Inexact)
+		if mode.needsInc(arith.BigAbsCmp(*r.Lsh(&r, 1), p), pos, z.lastDigit()) {
+			if pos {
+				z.mantissa.Add(&z.mantissa, oneInt)
+			} else {
+				z.mantissa.Sub(&z.mantissa, oneInt)
+			}
+		}
+	}
+	switch {
+	case z.mantissa.Sign() == 0:
+		z.setZero(neg)
+	case z.mantissa.IsInt64() && z.mantissa.Int64() != c.Inflated:
+		z.compact = z.mantissa.Int64()
+	}
+	return z
+}
+
+// RoundToScale rounds z to the given scale using mode and returns z. In other
+// words, it removes all but scale digits after the radix, or, if scale is
+// negative, replaces the last -scale digits before the radix with zeros.
+// Nothing happens if z has no more than scale digits after the radix.
+// Otherwise, Rounded is raised, as is Inexact if any removed digit was
+// nonzero.
+func (z *Big) RoundToScale(scale int32, mode RoundingMode) *Big {
+	if z.checkNaNs(z) || z.form != finite || z.scale <= scale {
+		return z
+	}
+	return z.shrink(int64(z.scale)-int64(scale), mode)
+}
+
+// RoundToInt sets z to x rounded to an integer using z's RoundingMode and
+// returns z. It implements the IEEE 754-2008 roundToIntegral operations, so
+// unlike RoundToScale it doesn't raise Inexact or Rounded.
+func (z *Big) RoundToInt(x *Big) *Big {
+	return z.roundToInt(x, z.ctx.Mode)
+}
+
+// RoundToIntExact sets z to x rounded to an integer using z's RoundingMode
+// and returns z and a bool that is false if the result differs from x. It
+// implements the IEEE 754-2008 roundToIntegralExact operation, which signals
+// inexact exactly when the bool is false: if so, Inexact and Rounded are
+// raised.
+func (z *Big) RoundToIntExact(x *Big) (*Big, bool) {
+	exact := x.form != finite || x.IsInt()
+	z.roundToInt(x, z.ctx.Mode)
+	if !exact {
+		z.raise(Inexact | Rounded)
+	}
+	return z, exact
+}
+
+// Ceil sets z to the least integer value greater than or equal to x and
+// returns z.
+func (z *Big) Ceil(x *Big) *Big {
+	return z.roundToInt(x, ToPositiveInf)
+}
+
+// Floor sets z to the greatest integer value less than or equal to x and
+// returns z.
+func (z *Big) Floor(x *Big) *Big {
+	return z.roundToInt(x, ToNegativeInf)
+}
+
+// Trunc sets z to x with its fractional part removed and returns z.
+func (z *Big) Trunc(x *Big) *Big {
+	return z.roundToInt(x, ToZero)
+}
+
+func (z *Big) roundToInt(x *Big, mode RoundingMode) *Big {
+	if z.checkNaNs(x) {
+		return z
+	}
+	// Round without raising or trapping any conditions. Setting the mode
+	// also keeps Unneeded from trapping Inexact if mode is explicit.
+	ctx := z.ctx
+	z.Set(x)
+	z.ctx = ctx
+	z.ctx.Traps = 0
+	z.ctx.Mode = mode
+	z.RoundToScale(0, mode)
+	z.ctx = ctx
+	return z
 }
@@ -68,11 +68,14 @@ func ParseCases(r io.Reader) (cases []Case, err error) {
 			return nil, fmt.Errorf("invalid line pre-trap: %s", line)
 		}
 
+		// The trapped exceptions are optional, so only consume them if they
+		// are present. Otherwise, this is the first input.
 		c.Trap, ok = valToException[string(line[:i])]
-		if !ok {
+		if ok {
+			line = line[i+1:]
+		} else {
 			c.Trap = None
 		}
-		line = line[i+1:]
 
 		i = bytes.Index(line, []byte{'-', '>'})
 		if i < 0 {
@@ -100,8 +103,9 @@ func ParseCases(r io.Reader) (cases []Case, err error) {
 		}
 		cases = append(cases, c)
 		// Reset the inputs otherwise we end up with a *ton* of inputs that's
-		// 1) incorrect, and 2) makes 500MB+ files.
-		c.Inputs = c.Inputs[0:0]
+		// 1) incorrect, and 2) makes 500MB+ files. Don't reuse the backing
+		// array, which is shared with the case we just appended.
+		c.Inputs = nil
 	}
 	return cases, s.Err()
 }
@@ -136,7 +140,7 @@ func (i Data) Inf() (int, bool) {
 	if len(i) != 4 {
 		return 0, false
 	}
-	if strings.EqualFold(string(i), "+Inf") {
+	if strings.EqualFold(string(i), "-Inf") {
 		return -1, true
 	}
 	if strings.EqualFold(string(i), "+Inf") {
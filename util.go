@@ -5,9 +5,11 @@ import (
 	"io"
 	"math"
 	"math/big"
+	"math/bits"
 
 	"github.com/EricLagergren/decimal/internal/arith"
 	"github.com/EricLagergren/decimal/internal/arith/checked"
+	"github.com/EricLagergren/decimal/internal/arith/pow"
 	"github.com/EricLagergren/decimal/internal/c"
 )
 
@@ -18,6 +20,22 @@ func (z *Big) ez() bool {
 	return z.Sign() == 0
 }
 
+// isZero returns true if x is ±0. Unlike ez, it's false if x is ±Inf or NaN.
+func (x *Big) isZero() bool {
+	return x.form == zero || (x.form == finite && x.ez())
+}
+
+// setZero sets z to -0 if neg is true and +0 otherwise, and returns z. z's
+// scale is unchanged.
+func (z *Big) setZero(neg bool) *Big {
+	z.form = zero
+	z.compact = 0
+	if neg {
+		z.compact = -1
+	}
+	return z
+}
+
 // ltz returns true if z < 0
 func (z *Big) ltz() bool {
 	return z.Sign() < 0
@@ -38,6 +56,12 @@ func (z *Big) gtez() bool {
 	return z.Sign() >= 0
 }
 
+// adjusted returns the adjusted exponent of x. That is, the exponent of x
+// when it is written in scientific notation with one digit before the radix.
+func (x *Big) adjusted() int64 {
+	return int64(x.Prec()) - int64(x.scale) - 1
+}
+
 // cmpNorm compares x and y in the range [0.1, 0.999...] and
 // returns true if x > y.
 func cmpNorm(x int64, xs int32, y int64, ys int32) (ok bool) {
@@ -72,11 +96,149 @@ func cmpNormBig(x *big.Int, xs int32, y *big.Int, ys int32) (ok bool) {
 	return x.Cmp(checked.MulBigPow10(y1, diff)) > 0
 }
 
+// infSign returns ±1 if x is ±Inf and 0 otherwise.
+func infSign(x *Big) int {
+	if x.form != inf {
+		return 0
+	}
+	if x.SignBit() {
+		return -1
+	}
+	return +1
+}
+
+// cmpAbs compares |x| and |y|, both of which must be finite and non-zero.
+func cmpAbs(x, y *Big) int {
+	if xa, ya := x.adjusted(), y.adjusted(); xa != ya {
+		if xa > ya {
+			return +1
+		}
+		return -1
+	}
+
+	// x and y have the same number of integral digits, so the one with the
+	// larger scale has more digits and shifting the other left by the
+	// difference lines them up.
+	diff := int64(x.scale) - int64(y.scale)
+	if x.isCompact() && y.isCompact() {
+		// The shifted value has as many digits as the other, so it's
+		// < 10^19 and fits into a uint64.
+		xc, yc := uabs(x.compact), uabs(y.compact)
+		if diff > 0 {
+			p, _ := pow.Ten64(diff)
+			yc *= uint64(p)
+		} else if diff < 0 {
+			p, _ := pow.Ten64(-diff)
+			xc *= uint64(p)
+		}
+		switch {
+		case xc > yc:
+			return +1
+		case xc < yc:
+			return -1
+		}
+		return 0
+	}
+
+	var xb, yb [2]big.Word
+	xw, yw := x.absWords(&xb), y.absWords(&yb)
+	if diff < 0 {
+		return -cmpMulPow10(yw, xw, -diff)
+	}
+	return cmpMulPow10(xw, yw, diff)
+}
+
+// absWords returns the little-endian words of |x|, using buf for compact
+// values.
+func (x *Big) absWords(buf *[2]big.Word) []big.Word {
+	if x.isInflated() {
+		return x.mantissa.Bits()
+	}
+	u := uabs(x.compact)
+	if bits.UintSize == 64 {
+		buf[0] = big.Word(u)
+		return buf[:1]
+	}
+	buf[0], buf[1] = big.Word(u), big.Word(u>>32)
+	return buf[:2]
+}
+
+// cmpMulPow10 compares x and y * 10**n, where x and y are the little-endian
+// words of non-negative integers. The product is computed a column at a time
+// and never stored, so cmpMulPow10 does not allocate.
+func cmpMulPow10(x, y []big.Word, n int64) int {
+	p := pow.BigTen(n)
+	pw := p.Bits()
+
+	var (
+		r   int
+		acc [3]uint // accumulated column sum and carries
+	)
+	m := len(y) + len(pw)
+	if len(x) > m {
+		m = len(x)
+	}
+	for i := 0; i < m; i++ {
+		j0 := i - len(pw) + 1
+		if j0 < 0 {
+			j0 = 0
+		}
+		for j := j0; j <= i && j < len(y); j++ {
+			hi, lo := bits.Mul(uint(y[j]), uint(pw[i-j]))
+			var c uint
+			acc[0], c = bits.Add(acc[0], lo, 0)
+			acc[1], c = bits.Add(acc[1], hi, c)
+			acc[2] += c
+		}
+		var xi uint
+		if i < len(x) {
+			xi = uint(x[i])
+		}
+		// Later (more significant) words take precedence.
+		if xi > acc[0] {
+			r = +1
+		} else if xi < acc[0] {
+			r = -1
+		}
+		acc[0], acc[1], acc[2] = acc[1], acc[2], 0
+	}
+	return r
+}
+
+// uabs returns |x| as a uint64.
+func uabs(x int64) uint64 {
+	if x < 0 {
+		return -uint64(x)
+	}
+	return uint64(x)
+}
+
+// lastDigit returns the absolute value of x's least significant digit. x must
+// be finite.
+func (x *Big) lastDigit() int64 {
+	if x.isCompact() {
+		return arith.Abs(x.compact % 10)
+	}
+	var r big.Int
+	return arith.Abs(r.Rem(&x.mantissa, c.TenInt).Int64())
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 type buffer struct{ bytes.Buffer }
 
 func (b *buffer) String() string {
-	// Trim zeros.
+	// Trim zeros, but only those after the radix. Zeros at the end of
+	// an exponent are significant.
 	buf := b.Bytes()
+	if bytes.IndexByte(buf, '.') < 0 || bytes.IndexAny(buf, "eE") >= 0 {
+		return b.Buffer.String()
+	}
 	i := len(buf) - 1
 	for ; i >= 0 && buf[i] == '0'; i-- {
 	}
@@ -202,6 +364,50 @@ func bigIntFromFloat(f float64) *big.Int {
 	return a.Lsh(&a, uint(shift))
 }
 
+// setFloat64Exact sets z to the exact decimal value of f, which must be
+// finite, and returns z.
+func (z *Big) setFloat64Exact(f float64) *Big {
+	// f == m * 2**exp with m a 53-bit integer.
+	frac, exp := math.Frexp(math.Abs(f))
+	m := uint64(math.Ldexp(frac, 53))
+	exp -= 53
+	for m != 0 && m&1 == 0 && exp < 0 {
+		m >>= 1
+		exp++
+	}
+
+	// m * 2**exp == m * 5**-exp * 10**exp
+	if exp < 0 && exp >= -27 {
+		p := uint64(1)
+		for i := exp; i < 0; i++ {
+			p *= 5
+		}
+		if hi, lo := bits.Mul64(m, p); hi == 0 && lo <= math.MaxInt64 {
+			v := int64(lo)
+			if f < 0 {
+				v = -v
+			}
+			return z.SetMantScale(v, int32(-exp))
+		}
+	}
+
+	var b big.Int
+	b.SetUint64(m)
+	var scale int32
+	if exp > 0 {
+		b.Lsh(&b, uint(exp))
+	} else if exp < 0 {
+		var p big.Int
+		p.Exp(big.NewInt(5), big.NewInt(int64(-exp)), nil)
+		b.Mul(&b, &p)
+		scale = int32(-exp)
+	}
+	if f < 0 {
+		b.Neg(&b)
+	}
+	return z.SetBigMantScale(&b, scale)
+}
+
 func shiftRadixRight(x *Big, n int) bool {
 	ns, ok := checked.Sub32(x.Scale(), int32(n))
 	if !ok {